@@ -39,23 +39,48 @@ export const addCategory = (data: any) =>
   });
 export const getCategories = () =>
   apiRequest("/projects/categories", { method: "GET" });
-export const updateCategory = (id: string, data: any) =>
+// Updates send the version last read as If-Match; a 412 means someone else saved first
+// and the data should be reloaded.
+const ifMatch = (version: number) => ({ "If-Match": `"${version}"` });
+export const updateCategory = (id: string, data: any, version: number) =>
   apiRequest(`/projects/categories/${id}`, {
     method: "PUT",
+    headers: ifMatch(version),
     body: JSON.stringify(data),
   });
-export const deleteCategory = (id: string) =>
-  apiRequest(`/projects/categories/${id}`, { method: "DELETE" });
+// Categories with projects or service steps need either cascade or reassignTo;
+// dryRun reports what would be affected without deleting anything.
+export const deleteCategory = (
+  id: string,
+  options: { cascade?: boolean; reassignTo?: string; dryRun?: boolean } = {}
+) => {
+  const params = new URLSearchParams();
+  if (options.cascade) params.set("cascade", "true");
+  if (options.reassignTo) params.set("reassignTo", options.reassignTo);
+  if (options.dryRun) params.set("dryRun", "true");
+  const query = params.toString();
+  return apiRequest(`/projects/categories/${id}${query ? `?${query}` : ""}`, {
+    method: "DELETE",
+  });
+};
 export const getProjectsByCategory = (category: string) =>
   apiRequest(`/projects/${category}`, { method: "GET" });
+export const getProject = (category: string, idOrSlug: string) =>
+  apiRequest(`/projects/${category}/${idOrSlug}`, { method: "GET" });
 export const addProject = (category: string, data: any) =>
   apiRequest(`/projects/${category}`, {
     method: "POST",
     body: JSON.stringify(data),
   });
-export const updateProject = (category: string, id: string, data: any) =>
+export const updateProject = (
+  category: string,
+  id: string,
+  data: any,
+  version: number
+) =>
   apiRequest(`/projects/${category}/${id}`, {
     method: "PUT",
+    headers: ifMatch(version),
     body: JSON.stringify(data),
   });
 export const deleteProject = (category: string, id: string) =>
@@ -85,4 +110,192 @@ export const updateServiceStep = (
 export const deleteServiceStep = (category: string, stepId: string) =>
   apiRequest(`/servicesteps/${category}/service-steps/${stepId}`, {
     method: "DELETE",
-  });
\ No newline at end of file
+  });
+// Contacts API
+export const getContacts = (
+  options: { status?: string; assignedTo?: string; category?: string; page?: number; limit?: number } = {}
+) => {
+  const params = new URLSearchParams();
+  Object.entries(options).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") params.set(key, String(value));
+  });
+  const query = params.toString();
+  return apiRequest(`/contacts${query ? `?${query}` : ""}`, { method: "GET" });
+};
+export const getContact = (id: string) =>
+  apiRequest(`/contacts/${id}`, { method: "GET" });
+export const updateContactStatus = (id: string, status: string) =>
+  apiRequest(`/contacts/${id}/status`, {
+    method: "PUT",
+    body: JSON.stringify({ status }),
+  });
+export const assignContact = (id: string, assignedTo: string) =>
+  apiRequest(`/contacts/${id}/assignee`, {
+    method: "PUT",
+    body: JSON.stringify({ assignedTo }),
+  });
+export const addContactNote = (id: string, text: string) =>
+  apiRequest(`/contacts/${id}/notes`, {
+    method: "POST",
+    body: JSON.stringify({ text }),
+  });
+
+// Quote requests API
+export const getQuoteRequests = (
+  options: { status?: string; category?: string; page?: number; limit?: number } = {}
+) => {
+  const params = new URLSearchParams();
+  Object.entries(options).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") params.set(key, String(value));
+  });
+  const query = params.toString();
+  return apiRequest(`/quotes${query ? `?${query}` : ""}`, { method: "GET" });
+};
+export const getQuoteRequest = (id: string) =>
+  apiRequest(`/quotes/${id}`, { method: "GET" });
+export const updateQuoteStatus = (id: string, status: string, note?: string) =>
+  apiRequest(`/quotes/${id}/status`, {
+    method: "PUT",
+    body: JSON.stringify({ status, note }),
+  });
+
+// Dashboard API
+export const getDashboardStats = (options: { weeks?: number; months?: number } = {}) => {
+  const params = new URLSearchParams();
+  if (options.weeks) params.set("weeks", String(options.weeks));
+  if (options.months) params.set("months", String(options.months));
+  const query = params.toString();
+  return apiRequest(`/admin/stats${query ? `?${query}` : ""}`, { method: "GET" });
+};
+export const getAuditLog = (
+  filters: {
+    actor?: string;
+    action?: string;
+    entityType?: string;
+    entityId?: string;
+    from?: string;
+    to?: string;
+    page?: number;
+    limit?: number;
+  } = {}
+) => {
+  const params = new URLSearchParams();
+  Object.entries(filters).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") params.set(key, String(value));
+  });
+  const query = params.toString();
+  return apiRequest(`/admin/audit${query ? `?${query}` : ""}`, { method: "GET" });
+};
+
+// Analytics API
+export const getTopProjects = (
+  options: { days?: number; category?: string; sort?: string; limit?: number } = {}
+) => {
+  const params = new URLSearchParams();
+  Object.entries(options).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") params.set(key, String(value));
+  });
+  const query = params.toString();
+  return apiRequest(`/analytics/top${query ? `?${query}` : ""}`, { method: "GET" });
+};
+export const getAnalyticsTrends = (options: { days?: number; category?: string } = {}) => {
+  const params = new URLSearchParams();
+  if (options.days) params.set("days", String(options.days));
+  if (options.category) params.set("category", options.category);
+  const query = params.toString();
+  return apiRequest(`/analytics/trends${query ? `?${query}` : ""}`, { method: "GET" });
+};
+
+// Webhooks API
+export const getWebhooks = () => apiRequest("/webhooks", { method: "GET" });
+export const getWebhookEvents = () => apiRequest("/webhooks/events", { method: "GET" });
+export const createWebhook = (data: {
+  url: string;
+  events: string[];
+  description?: string;
+  active?: boolean;
+}) =>
+  apiRequest("/webhooks", {
+    method: "POST",
+    body: JSON.stringify(data),
+  });
+export const updateWebhook = (
+  id: string,
+  data: {
+    url?: string;
+    events?: string[];
+    description?: string;
+    active?: boolean;
+    rotateSecret?: boolean;
+  }
+) =>
+  apiRequest(`/webhooks/${id}`, {
+    method: "PUT",
+    body: JSON.stringify(data),
+  });
+export const deleteWebhook = (id: string) =>
+  apiRequest(`/webhooks/${id}`, { method: "DELETE" });
+export const getWebhookDeliveries = (
+  id: string,
+  options: { status?: string; event?: string; page?: number; limit?: number } = {}
+) => {
+  const params = new URLSearchParams();
+  Object.entries(options).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") params.set(key, String(value));
+  });
+  const query = params.toString();
+  return apiRequest(`/webhooks/${id}/deliveries${query ? `?${query}` : ""}`, { method: "GET" });
+};
+export const pingWebhook = (id: string) =>
+  apiRequest(`/webhooks/${id}/ping`, { method: "POST" });
+export const redeliverWebhook = (deliveryId: string) =>
+  apiRequest(`/webhooks/deliveries/${deliveryId}/redeliver`, { method: "POST" });
+
+// Live updates: EventSource cannot send headers, so the token goes in the query string.
+// The browser reconnects on its own and sends Last-Event-ID to catch up; an event of type
+// "reset" means too much was missed and the data should be reloaded.
+export const openEventStream = (
+  onEvent: (event: any) => void,
+  options: { category?: string; types?: string[] } = {}
+) => {
+  const params = new URLSearchParams();
+  const token = Cookies.get("auth_token");
+  if (token) params.set("access_token", token);
+  if (options.category) params.set("category", options.category);
+  if (options.types?.length) params.set("types", options.types.join(","));
+  const source = new EventSource(`${process.env.NEXT_PUBLIC_API_URL}/events?${params.toString()}`);
+  source.onmessage = (message) => onEvent(JSON.parse(message.data));
+  return source;
+};
+
+// Site settings: contact channels, social links, hours, hero slides and SEO defaults.
+// The update replaces the whole document; send the version last read to avoid
+// overwriting someone else's changes.
+export const getSiteSettings = () => apiRequest("/settings", { method: "GET" });
+export const updateSiteSettings = (data: {
+  contacts: { type: string; label?: string; value: string; url?: string; qrImageUrl?: string }[];
+  social: { platform: string; label?: string; url: string }[];
+  businessHours: { day: string; open?: string; close?: string; closed: boolean }[];
+  hoursNote?: string;
+  heroSlides: {
+    title: string;
+    subtitle?: string;
+    text?: string;
+    imageUrl?: string;
+    buttonLabel?: string;
+    buttonUrl?: string;
+  }[];
+  footerText?: string;
+  seo: { title: string; description?: string; imageUrl?: string };
+  version: number;
+}) =>
+  apiRequest("/settings", {
+    method: "PUT",
+    body: JSON.stringify(data),
+  });
+export const getSiteSettingsRevisions = () =>
+  apiRequest("/settings/revisions", { method: "GET" });
+export const diffSiteSettingsRevisions = (from: number, to: number) =>
+  apiRequest(`/settings/revisions/diff?from=${from}&to=${to}`, { method: "GET" });
+export const restoreSiteSettingsRevision = (revision: number) =>
+  apiRequest(`/settings/revisions/${revision}/restore`, { method: "POST" });
@@ -33,6 +33,13 @@ import {
   TableRow,
 } from "@/components/ui/table";
 import { Card, CardContent } from "@/components/ui/card";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
 import {
   addCategory,
   deleteCategory,
@@ -43,6 +50,7 @@ import {
 interface Category {
   ID: string;
   NameCategory: string;
+  Version?: number;
   CreatedAt?: string;
   UpdatedAt?: string;
 }
@@ -51,11 +59,25 @@ interface CategoryResponse {
   data: Category;
 }
 
+// What deleting a category would touch, as reported by a dry run
+interface DeletionReport {
+  allowed: boolean;
+  projects: { _id: string; title: string }[];
+  serviceSteps: { _id: string; title: string }[];
+  subcategories: { _id: string; title: string }[];
+  nameConflicts?: string[];
+}
+
+type DeleteStrategy = "reassign" | "cascade";
+
 export default function CategoriesPage() {
   const [categories, setCategories] = useState<Category[]>([]);
   const [searchQuery, setSearchQuery] = useState("");
   const [isLoading, setIsLoading] = useState(true);
   const [categoryToDelete, setCategoryToDelete] = useState<string | null>(null);
+  const [deleteReport, setDeleteReport] = useState<DeletionReport | null>(null);
+  const [deleteStrategy, setDeleteStrategy] = useState<DeleteStrategy>("reassign");
+  const [reassignTo, setReassignTo] = useState("");
   const [isFormOpen, setIsFormOpen] = useState(false);
   const [formCategory, setFormCategory] = useState<Category | null>(null);
   const [formName, setFormName] = useState("");
@@ -93,7 +115,11 @@ export default function CategoriesPage() {
       let data: CategoryResponse;
       if (formCategory) {
         // Edit category
-        data = await updateCategory(formCategory.ID, body);
+        data = await updateCategory(
+          formCategory.ID,
+          body,
+          formCategory.Version ?? 0
+        );
         setCategories(
           categories.map((c) => (c.ID === formCategory.ID ? data.data : c))
         );
@@ -114,35 +140,75 @@ export default function CategoriesPage() {
       setFormCategory(null);
       setFormName("");
     } catch (error: any) {
+      const stale = error.message?.includes("412");
       toast({
         title: "Error",
-        description: error.message || "Failed to save category.",
+        description: stale
+          ? "This category was changed by someone else. Reload the page and try again."
+          : error.message || "Failed to save category.",
         variant: "destructive",
       });
     }
   };
 
+  // Ask the API what a delete would touch before offering it
+  const prepareDelete = async (id: string) => {
+    setCategoryToDelete(id);
+    setDeleteReport(null);
+    setDeleteStrategy("reassign");
+    setReassignTo("");
+    try {
+      const response = await deleteCategory(id, { dryRun: true });
+      setDeleteReport(response.data);
+    } catch (error: any) {
+      toast({
+        title: "Error",
+        description: error.message || "Failed to check category content.",
+        variant: "destructive",
+      });
+    }
+  };
+
+  const deleteHasContent =
+    deleteReport !== null &&
+    (deleteReport.projects.length > 0 || deleteReport.serviceSteps.length > 0);
+  const deleteBlocked =
+    deleteReport === null ||
+    (deleteReport.nameConflicts?.length ?? 0) > 0 ||
+    (deleteHasContent && deleteStrategy === "reassign" && !reassignTo);
+
   // Handle delete category
   const handleDeleteCategory = async () => {
-    if (categoryToDelete !== null) {
+    if (categoryToDelete !== null && !deleteBlocked) {
       try {
-        await deleteCategory(categoryToDelete);
+        const options = !deleteHasContent
+          ? {}
+          : deleteStrategy === "cascade"
+          ? { cascade: true }
+          : { reassignTo };
+        await deleteCategory(categoryToDelete, options);
         setCategories(
           categories.filter((category) => category.ID !== categoryToDelete)
         );
         toast({
           title: "Category deleted",
-          description:
-            "The category and associated projects and service steps have been deleted.",
+          description: !deleteHasContent
+            ? "The category has been deleted."
+            : deleteStrategy === "cascade"
+            ? "The category and associated projects and service steps have been deleted."
+            : "The category has been deleted and its content moved.",
         });
       } catch (error: any) {
         toast({
           title: "Error",
-          description: error.message || "Failed to delete category.",
+          description: error.message?.includes("409")
+            ? "The category changed while deleting. Please try again."
+            : error.message || "Failed to delete category.",
           variant: "destructive",
         });
       } finally {
         setCategoryToDelete(null);
+        setDeleteReport(null);
       }
     }
   };
@@ -293,7 +359,7 @@ export default function CategoriesPage() {
                                       <DropdownMenuItem
                                         onSelect={(e) => {
                                           e.preventDefault();
-                                          setCategoryToDelete(category.ID);
+                                          prepareDelete(category.ID);
                                         }}
                                         className="text-[#111827] dark:text-[#D1D5DB]"
                                       >
@@ -307,23 +373,72 @@ export default function CategoriesPage() {
                                           Are you absolutely sure?
                                         </AlertDialogTitle>
                                         <AlertDialogDescription className="text-[#6B7280] dark:text-[#9CA3AF]">
-                                          This action cannot be undone. This
-                                          will permanently delete the category
-                                          and all associated projects and
-                                          service steps.
+                                          {deleteReport === null
+                                            ? "Checking what this category contains..."
+                                            : `This category has ${deleteReport.projects.length} projects, ${deleteReport.serviceSteps.length} service steps and ${deleteReport.subcategories.length} subcategories. Subcategories move up one level.`}
                                         </AlertDialogDescription>
                                       </AlertDialogHeader>
+                                      {deleteReport?.nameConflicts &&
+                                        deleteReport.nameConflicts.length > 0 && (
+                                          <p className="text-sm text-red-600 dark:text-red-400">
+                                            Rename these subcategories first,
+                                            their names are already used one
+                                            level up:{" "}
+                                            {deleteReport.nameConflicts.join(", ")}
+                                          </p>
+                                        )}
+                                      {deleteHasContent && (
+                                        <div className="space-y-3 text-sm text-[#111827] dark:text-[#D1D5DB]">
+                                          <label className="flex items-center gap-2">
+                                            <input
+                                              type="radio"
+                                              checked={deleteStrategy === "reassign"}
+                                              onChange={() => setDeleteStrategy("reassign")}
+                                            />
+                                            Move projects and service steps to
+                                          </label>
+                                          <Select
+                                            value={reassignTo}
+                                            onValueChange={setReassignTo}
+                                            disabled={deleteStrategy !== "reassign"}
+                                          >
+                                            <SelectTrigger className="bg-white dark:bg-[#374151] border-[#D1D5DB] dark:border-[#4B5563]">
+                                              <SelectValue placeholder="Choose a category" />
+                                            </SelectTrigger>
+                                            <SelectContent>
+                                              {categories
+                                                .filter((c) => c.ID !== category.ID)
+                                                .map((c) => (
+                                                  <SelectItem key={c.ID} value={c.ID}>
+                                                    {c.NameCategory}
+                                                  </SelectItem>
+                                                ))}
+                                            </SelectContent>
+                                          </Select>
+                                          <label className="flex items-center gap-2">
+                                            <input
+                                              type="radio"
+                                              checked={deleteStrategy === "cascade"}
+                                              onChange={() => setDeleteStrategy("cascade")}
+                                            />
+                                            Permanently delete them and their
+                                            files. This cannot be undone.
+                                          </label>
+                                        </div>
+                                      )}
                                       <AlertDialogFooter>
                                         <AlertDialogCancel
-                                          onClick={() =>
-                                            setCategoryToDelete(null)
-                                          }
+                                          onClick={() => {
+                                            setCategoryToDelete(null);
+                                            setDeleteReport(null);
+                                          }}
                                           className="bg-white dark:bg-[#374151] text-[#111827] dark:text-[#D1D5DB] border-[#D1D5DB] dark:border-[#4B5563]"
                                         >
                                           Cancel
                                         </AlertDialogCancel>
                                         <AlertDialogAction
                                           onClick={handleDeleteCategory}
+                                          disabled={deleteBlocked}
                                           className="bg-red-600 text-white hover:bg-red-700 dark:bg-red-700 dark:hover:bg-red-800"
                                         >
                                           Delete
@@ -20,12 +20,19 @@ interface Category {
   NameCategory: string;
 }
 
+interface Section {
+  text: string;
+  headings: string[];
+}
+
 interface ServiceStep {
   _id: string;
   title: string;
+  sections?: Section[];
   subtitles: string[];
   headings: string[];
   createdAt: string;
+  version?: number;
 }
 
 interface EditServiceStepDialogProps {
@@ -36,6 +43,17 @@ interface EditServiceStepDialogProps {
   onUpdate: (updatedStep: ServiceStep) => void;
 }
 
+// Steps saved before sections existed only carry the flat lists: each subtitle becomes
+// a section and the headings go under the last one, as the backend migration does.
+const sectionsFromFlat = (subtitles: string[], headings: string[]): Section[] => {
+  const sections = subtitles.map((text) => ({ text, headings: [] as string[] }));
+  if (headings.length > 0) {
+    if (sections.length === 0) sections.push({ text: "", headings: [] });
+    sections[sections.length - 1].headings.push(...headings);
+  }
+  return sections.length > 0 ? sections : [{ text: "", headings: [] }];
+};
+
 export default function EditServiceStepDialog({
   open,
   onOpenChange,
@@ -45,16 +63,22 @@ export default function EditServiceStepDialog({
 }: EditServiceStepDialogProps) {
   const { toast } = useToast();
   const [title, setTitle] = useState("");
-  const [subtitles, setSubtitles] = useState<string[]>([]);
-  const [headings, setHeadings] = useState<string[]>([]);
+  const [sections, setSections] = useState<Section[]>([]);
   const [categoryId, setCategoryId] = useState("");
   const [isLoading, setIsLoading] = useState(false);
 
   useEffect(() => {
     if (step) {
       setTitle(step.title);
-      setSubtitles(step.subtitles.length > 0 ? step.subtitles : [""]);
-      setHeadings(step.headings.length > 0 ? step.headings : [""]);
+      setSections(
+        (step.sections && step.sections.length > 0
+          ? step.sections
+          : sectionsFromFlat(step.subtitles, step.headings)
+        ).map((section) => ({
+          text: section.text,
+          headings: section.headings.length > 0 ? section.headings : [""],
+        }))
+      );
     }
   }, [step]);
 
@@ -92,28 +116,32 @@ export default function EditServiceStepDialog({
     }
   }, [open, categoryName, toast]);
 
-  const handleSubtitleChange = (index: number, value: string) => {
-    setSubtitles(subtitles.map((s, i) => (i === index ? value : s)));
+  const updateSection = (index: number, change: Partial<Section>) => {
+    setSections(sections.map((s, i) => (i === index ? { ...s, ...change } : s)));
   };
 
-  const handleHeadingChange = (index: number, value: string) => {
-    setHeadings(headings.map((h, i) => (i === index ? value : h)));
+  const addSection = () => {
+    setSections([...sections, { text: "", headings: [""] }]);
   };
 
-  const addSubtitle = () => {
-    setSubtitles([...subtitles, ""]);
+  const removeSection = (index: number) => {
+    setSections(sections.filter((_, i) => i !== index));
   };
 
-  const removeSubtitle = (index: number) => {
-    setSubtitles(subtitles.filter((_, i) => i !== index));
+  const handleHeadingChange = (sectionIndex: number, index: number, value: string) => {
+    updateSection(sectionIndex, {
+      headings: sections[sectionIndex].headings.map((h, i) => (i === index ? value : h)),
+    });
   };
 
-  const addHeading = () => {
-    setHeadings([...headings, ""]);
+  const addHeading = (sectionIndex: number) => {
+    updateSection(sectionIndex, { headings: [...sections[sectionIndex].headings, ""] });
   };
 
-  const removeHeading = (index: number) => {
-    setHeadings(headings.filter((_, i) => i !== index));
+  const removeHeading = (sectionIndex: number, index: number) => {
+    updateSection(sectionIndex, {
+      headings: sections[sectionIndex].headings.filter((_, i) => i !== index),
+    });
   };
 
   const handleSubmit = async (e: React.FormEvent) => {
@@ -131,13 +159,17 @@ export default function EditServiceStepDialog({
       return;
     }
 
-    const filteredSubtitles = subtitles.filter((s) => s.trim());
-    const filteredHeadings = headings.filter((h) => h.trim());
+    const filteredSections = sections
+      .map((section) => ({
+        text: section.text.trim(),
+        headings: section.headings.map((h) => h.trim()).filter((h) => h),
+      }))
+      .filter((section) => section.text || section.headings.length > 0);
 
-    if (filteredSubtitles.length === 0 && filteredHeadings.length === 0) {
+    if (filteredSections.length === 0) {
       toast({
         title: "Error",
-        description: "At least one subtitle or heading is required",
+        description: "At least one section with a subtitle or heading is required",
         variant: "destructive",
       });
       setIsLoading(false);
@@ -147,8 +179,8 @@ export default function EditServiceStepDialog({
     const payload = {
       categories: categoryId,
       title: title.trim(),
-      subtitles: filteredSubtitles,
-      headings: filteredHeadings,
+      sections: filteredSections,
+      version: step.version ?? 0,
     };
 
     try {
@@ -212,76 +244,77 @@ export default function EditServiceStepDialog({
           </div>
           <div>
             <label className="text-sm font-medium text-gray-900 dark:text-gray-100">
-              Subtitles
+              Sections
             </label>
-            {subtitles.map((subtitle, index) => (
-              <div key={index} className="flex items-center gap-2 mt-2">
-                <Input
-                  value={subtitle}
-                  onChange={(e) => handleSubtitleChange(index, e.target.value)}
-                  placeholder={`Subtitle ${index + 1}`}
-                  className="bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 border-gray-200 dark:border-gray-600 w-full max-w-md"
-                  disabled={isLoading}
-                />
-                {subtitles.length > 1 && (
-                  <Button
-                    type="button"
-                    variant="destructive"
-                    size="sm"
-                    onClick={() => removeSubtitle(index)}
+            {sections.map((section, sectionIndex) => (
+              <div
+                key={sectionIndex}
+                className="mt-2 space-y-2 rounded-md border border-gray-200 dark:border-gray-600 p-3"
+              >
+                <div className="flex items-center gap-2">
+                  <Input
+                    value={section.text}
+                    onChange={(e) => updateSection(sectionIndex, { text: e.target.value })}
+                    placeholder={`Subtitle ${sectionIndex + 1}`}
+                    className="bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 border-gray-200 dark:border-gray-600 w-full max-w-md"
                     disabled={isLoading}
-                    className="w-full sm:w-auto mt-2 sm:mt-0"
-                  >
-                    Delete Subtitle
-                  </Button>
-                )}
-              </div>
-            ))}
-            <Button
-              type="button"
-              variant="outline"
-              className="mt-4 w-full sm:w-auto"
-              onClick={addSubtitle}
-              disabled={isLoading}
-            >
-              Add Subtitle
-            </Button>
-          </div>
-          <div>
-            <label className="text-sm font-medium text-gray-900 dark:text-gray-100">
-              Headings
-            </label>
-            {headings.map((heading, index) => (
-              <div key={index} className="flex items-center gap-2 mt-2">
-                <Input
-                  value={heading}
-                  onChange={(e) => handleHeadingChange(index, e.target.value)}
-                  placeholder={`Heading ${index + 1}`}
-                  className="bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 border-gray-200 dark:border-gray-600 w-full max-w-md"
+                  />
+                  {sections.length > 1 && (
+                    <Button
+                      type="button"
+                      variant="destructive"
+                      size="sm"
+                      onClick={() => removeSection(sectionIndex)}
+                      disabled={isLoading}
+                      className="w-full sm:w-auto mt-2 sm:mt-0"
+                    >
+                      Delete Section
+                    </Button>
+                  )}
+                </div>
+                {section.headings.map((heading, index) => (
+                  <div key={index} className="flex items-center gap-2 pl-4">
+                    <Input
+                      value={heading}
+                      onChange={(e) => handleHeadingChange(sectionIndex, index, e.target.value)}
+                      placeholder={`Heading ${index + 1}`}
+                      className="bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 border-gray-200 dark:border-gray-600 w-full max-w-md"
+                      disabled={isLoading}
+                    />
+                    {section.headings.length > 1 && (
+                      <Button
+                        type="button"
+                        variant="destructive"
+                        size="sm"
+                        onClick={() => removeHeading(sectionIndex, index)}
+                        disabled={isLoading}
+                        className="w-full sm:w-auto mt-2 sm:mt-0"
+                      >
+                        Delete Heading
+                      </Button>
+                    )}
+                  </div>
+                ))}
+                <Button
+                  type="button"
+                  variant="outline"
+                  size="sm"
+                  className="ml-4 w-full sm:w-auto"
+                  onClick={() => addHeading(sectionIndex)}
                   disabled={isLoading}
-                />
-                {headings.length > 1 && (
-                  <Button
-                    type="button"
-                    variant="destructive"
-                    size="sm"
-                    onClick={() => removeHeading(index)}
-                    disabled={isLoading}
-                    className="w-full sm:w-auto mt-2 sm:mt-0"
-                  >
-                    Delete Heading
-                  </Button>
-                )}
+                >
+                  Add Heading
+                </Button>
               </div>
             ))}
             <Button
               type="button"
               variant="outline"
               className="mt-4 w-full sm:w-auto"
-              onClick={addHeading}
+              onClick={addSection}
               disabled={isLoading}
             >
-              Add Heading
+              Add Section
             </Button>
           </div>
           <AlertDialogFooter className="flex flex-col sm:flex-row sm:gap-2">
@@ -3,6 +3,7 @@ package configs
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -39,3 +40,15 @@ func EnvPort() string {
 	}
 	return port
 }
+
+// EnvTrustedProxies returns the proxy addresses or CIDR ranges in TRUSTED_PROXIES (comma
+// separated). X-Forwarded-For is only read from requests these proxies forward.
+func EnvTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
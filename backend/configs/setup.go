@@ -16,6 +16,21 @@ var (
 	ProjectsColl     *mongo.Collection
 	CategoriesColl   *mongo.Collection
 	ServiceStepsColl *mongo.Collection
+	RevisionsColl    *mongo.Collection
+	MigrationsColl   *mongo.Collection
+	TagsColl         *mongo.Collection
+	ContactsColl     *mongo.Collection
+	QuotesColl       *mongo.Collection
+	// Analytics rollups per project and day, and the hashed visitors seen per day
+	AnalyticsDailyColl    *mongo.Collection
+	AnalyticsVisitorsColl *mongo.Collection
+	// Who changed what from the admin, kept for review
+	AuditColl *mongo.Collection
+	// Registered webhook endpoints and the log of what was sent to them
+	WebhooksColl          *mongo.Collection
+	WebhookDeliveriesColl *mongo.Collection
+	// The single site settings document edited from the admin
+	SiteSettingsColl *mongo.Collection
 )
 
 // InitDB initializes the MongoDB connection and sets up collections
@@ -43,10 +58,32 @@ func InitDB() {
 	ProjectsColl = db.Collection("projects")
 	CategoriesColl = db.Collection("categories")
 	ServiceStepsColl = db.Collection("serviceSteps")
+	RevisionsColl = db.Collection("revisions")
+	MigrationsColl = db.Collection("migrations")
+	TagsColl = db.Collection("tags")
+	ContactsColl = db.Collection("contacts")
+	QuotesColl = db.Collection("quoteRequests")
+	AnalyticsDailyColl = db.Collection("analyticsDaily")
+	AnalyticsVisitorsColl = db.Collection("analyticsVisitors")
+	AuditColl = db.Collection("auditLog")
+	WebhooksColl = db.Collection("webhooks")
+	WebhookDeliveriesColl = db.Collection("webhookDeliveries")
+	SiteSettingsColl = db.Collection("siteSettings")
 
 	// Create indexes
 	_, err = ProjectsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.M{"category_id": 1}},
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "pinned", Value: -1}, {Key: "position", Value: 1}}},
+		// Partial so projects without a title (and so without a slug) do not collide
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}})},
+		{Keys: bson.M{"tags": 1}},
+		{Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "tags", Value: "text"},
+			{Key: "translations.en.title", Value: "text"},
+			{Key: "translations.en.description", Value: "text"},
+		}, Options: textIndexOptions("projects_text", bson.M{"title": 10, "translations.en.title": 10, "tags": 5, "description": 2, "translations.en.description": 2})},
 	})
 	if err != nil {
 		log.Fatal("Failed to create indexes for projects:", err)
@@ -55,21 +92,130 @@ func InitDB() {
 	_, err = ServiceStepsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.M{"project_id": 1}},
 		{Keys: bson.M{"category_id": 1}},
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "stepNumber", Value: 1}}},
+		{Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "sections.text", Value: "text"},
+			{Key: "sections.headings", Value: "text"},
+			{Key: "translations.en.title", Value: "text"},
+			{Key: "translations.en.sections.text", Value: "text"},
+			{Key: "translations.en.sections.headings", Value: "text"},
+		}, Options: textIndexOptions("serviceSteps_text", bson.M{"title": 10, "translations.en.title": 10, "sections.text": 3, "translations.en.sections.text": 3, "sections.headings": 1, "translations.en.sections.headings": 1})},
 	})
 	if err != nil {
 		log.Fatal("Failed to create indexes for serviceSteps:", err)
 	}
 
 	_, err = CategoriesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
-		{Keys: bson.M{"nameCategory": 1}, Options: options.Index().SetUnique(true)},
+		// Names are unique among siblings; root categories share a missing parentId
+		{Keys: bson.D{{Key: "parentId", Value: 1}, {Key: "nameCategory", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Partial so categories created before slugs existed do not collide until migrated
+		{Keys: bson.M{"slug": 1}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}})},
+		{Keys: bson.M{"previousSlugs": 1}},
+		{Keys: bson.D{
+			{Key: "nameCategory", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "translations.en.nameCategory", Value: "text"},
+			{Key: "translations.en.description", Value: "text"},
+		}, Options: textIndexOptions("categories_text", bson.M{"nameCategory": 10, "translations.en.nameCategory": 10, "description": 2, "translations.en.description": 2})},
 	})
 	if err != nil {
 		log.Fatal("Failed to create indexes for categories:", err)
 	}
 
+	_, err = TagsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"slug": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"name": 1}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for tags:", err)
+	}
+
+	_, err = ContactsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.M{"assignedTo": 1}},
+		{Keys: bson.M{"createdAt": -1}},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for contacts:", err)
+	}
+
+	_, err = QuotesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for quote requests:", err)
+	}
+
+	_, err = AnalyticsDailyColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "date", Value: 1}}},
+		{Keys: bson.M{"date": 1}},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for analyticsDaily:", err)
+	}
+
+	_, err = AnalyticsVisitorsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "project_id", Value: 1}, {Key: "visitor", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Visitor hashes are only needed for the day they count; drop them soon after
+		{Keys: bson.M{"createdAt": 1}, Options: options.Index().SetExpireAfterSeconds(2 * 24 * 60 * 60)},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for analyticsVisitors:", err)
+	}
+
+	_, err = AuditColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"createdAt": -1}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "entityType", Value: 1}, {Key: "entityId", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for audit log:", err)
+	}
+
+	_, err = WebhookDeliveriesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		// The delivery log is kept for 30 days
+		{Keys: bson.M{"createdAt": 1}, Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60)},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for webhookDeliveries:", err)
+	}
+
+	// Guards against a second settings document when two first saves race
+	_, err = SiteSettingsColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"key": 1},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for siteSettings:", err)
+	}
+
+	_, err = RevisionsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "entityType", Value: 1}, {Key: "entityId", Value: 1}, {Key: "number", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"createdAt": -1}},
+	})
+	if err != nil {
+		log.Fatal("Failed to create indexes for revisions:", err)
+	}
+
 	log.Println("Successfully connected to MongoDB")
 }
 
+// textIndexOptions configures a search index. Content mixes Thai and English, so no
+// language-specific stemming or stop words are applied; Thai queries are matched as
+// substrings by the search endpoint since the text index cannot split Thai words.
+func textIndexOptions(name string, weights bson.M) *options.IndexOptions {
+	return options.Index().
+		SetName(name).
+		SetWeights(weights).
+		SetDefaultLanguage("none").
+		SetLanguageOverride("searchLanguage")
+}
+
 // DisconnectDB closes the MongoDB connection
 func DisconnectDB() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/middleware"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxAnalyticsBatch = 20
+	maxAnalyticsDays  = 365
+	maxAnalyticsTop   = 50
+)
+
+type AnalyticsEventRequest struct {
+	Type      string `json:"type"`
+	ProjectID string `json:"projectId"`
+}
+
+// AnalyticsBeaconRequest carries either one event in its own fields or a batch in Events.
+type AnalyticsBeaconRequest struct {
+	AnalyticsEventRequest
+	Events []AnalyticsEventRequest `json:"events"`
+}
+
+type ProjectEngagement struct {
+	ProjectID  primitive.ObjectID     `json:"projectId" bson:"_id"`
+	Title      string                 `json:"title,omitempty"`
+	Slug       string                 `json:"slug,omitempty"`
+	CategoryID primitive.ObjectID     `json:"categoryId" bson:"category_id"`
+	Counts     models.AnalyticsCounts `json:"counts" bson:"counts"`
+	Visitors   int                    `json:"visitors" bson:"visitors"`
+}
+
+type EngagementDay struct {
+	Date     string                 `json:"date"`
+	Counts   models.AnalyticsCounts `json:"counts"`
+	Visitors int                    `json:"visitors"`
+}
+
+type CategoryTrend struct {
+	CategoryID   primitive.ObjectID `json:"categoryId"`
+	Slug         string             `json:"slug"`
+	NameCategory string             `json:"nameCategory"`
+	Days         []EngagementDay    `json:"days"`
+}
+
+// analyticsSort maps the ?sort= values of the top projects endpoint to rollup fields.
+var analyticsSort = map[string]string{
+	"views":      "counts.views",
+	"modalOpens": "counts.modalOpens",
+	"videoPlays": "counts.videoPlays",
+	"visitors":   "visitors",
+}
+
+// TrackAnalyticsHandler records beacon events from the public site. It always answers
+// 204 for well-formed requests, so the site never waits on or retries analytics.
+func TrackAnalyticsHandler(c *fiber.Ctx) error {
+	// navigator.sendBeacon posts text/plain, so the body is decoded regardless of Content-Type
+	var req AnalyticsBeaconRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	events := req.Events
+	if req.Type != "" {
+		events = append(events, req.AnalyticsEventRequest)
+	}
+	if len(events) == 0 || len(events) > maxAnalyticsBatch {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Between 1 and %d events are required", maxAnalyticsBatch),
+		})
+	}
+
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if models.IsBotUserAgent(userAgent) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(events))
+	for _, event := range events {
+		if _, ok := models.AnalyticsCounterField(event.Type); !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Unknown event type %q", event.Type),
+			})
+		}
+		objID, err := primitive.ObjectIDFromHex(event.ProjectID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid project ID",
+			})
+		}
+		ids = append(ids, objID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only published projects are tracked; events for anything else are dropped
+	cursor, err := configs.ProjectsColl.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "published": true},
+		options.Find().SetProjection(bson.M{"category_id": 1}))
+	if err != nil {
+		log.Printf("TrackAnalyticsHandler: Failed to fetch projects: %v", err)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("TrackAnalyticsHandler: Failed to process projects: %v", err)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	categoryOf := map[primitive.ObjectID]primitive.ObjectID{}
+	for _, project := range projects {
+		categoryOf[project.ID] = project.CategoryID
+	}
+
+	now := time.Now()
+	day := now.In(studioLocation()).Format("2006-01-02")
+	visitor := models.VisitorHash(configs.EnvSecret(), day, middleware.ClientIP(c), userAgent)
+
+	for i, event := range events {
+		categoryID, ok := categoryOf[ids[i]]
+		if !ok {
+			continue
+		}
+		field, _ := models.AnalyticsCounterField(event.Type)
+		inc := bson.M{"counts." + field: 1}
+
+		// The visitor hash is kept just long enough to count each visitor once per day
+		_, err := configs.AnalyticsVisitorsColl.InsertOne(ctx, bson.M{
+			"date":       day,
+			"project_id": ids[i],
+			"visitor":    visitor,
+			"createdAt":  now,
+		})
+		if err == nil {
+			inc["visitors"] = 1
+		} else if !mongo.IsDuplicateKeyError(err) {
+			log.Printf("TrackAnalyticsHandler: Failed to record visitor: %v", err)
+		}
+
+		_, err = configs.AnalyticsDailyColl.UpdateOne(ctx,
+			bson.M{"project_id": ids[i], "date": day},
+			bson.M{
+				"$inc":         inc,
+				"$setOnInsert": bson.M{"category_id": categoryID},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			log.Printf("TrackAnalyticsHandler: Failed to update rollup for project %s: %v", ids[i].Hex(), err)
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// analyticsRange reads ?days= and ?category= into a rollup filter. It returns the days
+// shown so callers can fill in days without events.
+func analyticsRange(ctx context.Context, c *fiber.Ctx, handler string) (bson.M, []string, *models.Category, error) {
+	days := c.QueryInt("days", 30)
+	if days < 1 || days > maxAnalyticsDays {
+		days = 30
+	}
+	periods := models.DayPeriods(time.Now().In(studioLocation()), days)
+	filter := bson.M{"date": bson.M{"$gte": periods[0]}}
+
+	if categorySlug := c.Query("category"); categorySlug != "" {
+		var category models.Category
+		if _, err := findCategoryBySlug(ctx, categorySlug, &category); err != nil {
+			log.Printf("%s: Category %s not found: %v", handler, categorySlug, err)
+			return nil, nil, nil, fmt.Errorf("Category not found")
+		}
+		filter["category_id"] = category.ID
+		return filter, periods, &category, nil
+	}
+	return filter, periods, nil, nil
+}
+
+func GetTopProjectsHandler(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, _, _, err := analyticsRange(ctx, c, "GetTopProjectsHandler")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	sortField, ok := analyticsSort[c.Query("sort", "views")]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "sort must be views, modalOpens, videoPlays or visitors",
+		})
+	}
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > maxAnalyticsTop {
+		limit = 10
+	}
+
+	cursor, err := configs.AnalyticsDailyColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$project_id",
+			"category_id": bson.M{"$first": "$category_id"},
+			"views":       bson.M{"$sum": "$counts.views"},
+			"modalOpens":  bson.M{"$sum": "$counts.modalOpens"},
+			"videoPlays":  bson.M{"$sum": "$counts.videoPlays"},
+			"visitors":    bson.M{"$sum": "$visitors"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"category_id": 1,
+			"visitors":    1,
+			"counts": bson.M{
+				"views":      "$views",
+				"modalOpens": "$modalOpens",
+				"videoPlays": "$videoPlays",
+			},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: sortField, Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		log.Printf("GetTopProjectsHandler: Failed to aggregate analytics: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch analytics",
+		})
+	}
+	top := []ProjectEngagement{}
+	if err := cursor.All(ctx, &top); err != nil {
+		log.Printf("GetTopProjectsHandler: Failed to process analytics: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process analytics",
+		})
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(top))
+	for _, entry := range top {
+		ids = append(ids, entry.ProjectID)
+	}
+	projectCursor, err := configs.ProjectsColl.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"title": 1, "slug": 1, "category_id": 1}))
+	if err == nil {
+		var projects []models.Project
+		if err := projectCursor.All(ctx, &projects); err == nil {
+			byID := map[primitive.ObjectID]models.Project{}
+			for _, project := range projects {
+				byID[project.ID] = project
+			}
+			for i := range top {
+				top[i].Title = byID[top[i].ProjectID].Title
+				top[i].Slug = byID[top[i].ProjectID].Slug
+			}
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Top projects fetched successfully",
+		"data":    top,
+		"count":   len(top),
+	})
+}
+
+func GetAnalyticsTrendsHandler(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, periods, category, err := analyticsRange(ctx, c, "GetAnalyticsTrendsHandler")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	cursor, err := configs.AnalyticsDailyColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        bson.M{"category_id": "$category_id", "date": "$date"},
+			"views":      bson.M{"$sum": "$counts.views"},
+			"modalOpens": bson.M{"$sum": "$counts.modalOpens"},
+			"videoPlays": bson.M{"$sum": "$counts.videoPlays"},
+			"visitors":   bson.M{"$sum": "$visitors"},
+		}}},
+	})
+	if err != nil {
+		log.Printf("GetAnalyticsTrendsHandler: Failed to aggregate analytics: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch analytics",
+		})
+	}
+	var grouped []struct {
+		Key struct {
+			CategoryID primitive.ObjectID `bson:"category_id"`
+			Date       string             `bson:"date"`
+		} `bson:"_id"`
+		models.AnalyticsCounts `bson:",inline"`
+		Visitors               int `bson:"visitors"`
+	}
+	if err := cursor.All(ctx, &grouped); err != nil {
+		log.Printf("GetAnalyticsTrendsHandler: Failed to process analytics: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process analytics",
+		})
+	}
+
+	categories := []models.Category{}
+	if category != nil {
+		categories = append(categories, *category)
+	} else if categories, err = loadAllCategories(ctx); err != nil {
+		log.Printf("GetAnalyticsTrendsHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch categories",
+		})
+	}
+
+	byDay := map[primitive.ObjectID]map[string]EngagementDay{}
+	for _, group := range grouped {
+		if byDay[group.Key.CategoryID] == nil {
+			byDay[group.Key.CategoryID] = map[string]EngagementDay{}
+		}
+		byDay[group.Key.CategoryID][group.Key.Date] = EngagementDay{Date: group.Key.Date, Counts: group.AnalyticsCounts, Visitors: group.Visitors}
+	}
+
+	trends := make([]CategoryTrend, 0, len(categories))
+	for _, cat := range categories {
+		trend := CategoryTrend{CategoryID: cat.ID, Slug: cat.Slug, NameCategory: cat.NameCategory, Days: make([]EngagementDay, len(periods))}
+		for i, date := range periods {
+			day, ok := byDay[cat.ID][date]
+			if !ok {
+				day = EngagementDay{Date: date}
+			}
+			trend.Days[i] = day
+		}
+		trends = append(trends, trend)
+	}
+	sort.SliceStable(trends, func(i, j int) bool {
+		return trends[i].NameCategory < trends[j].NameCategory
+	})
+
+	return c.JSON(fiber.Map{
+		"message": "Analytics trends fetched successfully",
+		"data":    trends,
+		"count":   len(trends),
+	})
+}
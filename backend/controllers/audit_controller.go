@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/middleware"
+	"backend/models"
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxAuditLimit = 100
+
+// auditSnapshot loads a document as an audit summary; it is nil when the document does not exist.
+func auditSnapshot(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID) bson.M {
+	var doc bson.M
+	if err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		return nil
+	}
+	return models.AuditSummary(doc)
+}
+
+// auditSummaryOf summarizes an entity already in memory, e.g. one that was just inserted.
+func auditSummaryOf(entity interface{}) bson.M {
+	raw, err := bson.Marshal(entity)
+	if err != nil {
+		return nil
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	return models.AuditSummary(doc)
+}
+
+// recordAudit persists an audit entry for a change that has been written. Pass nil for
+// before on creates and for after on deletes. Failing to write the entry is logged but
+// does not fail the request, the change itself already happened.
+func recordAudit(c *fiber.Ctx, actor, action, entityType string, entityID primitive.ObjectID, before, after bson.M, detail string) {
+	// Actors are stored lowercased, as the actor filter matches them; emails keep the case
+	// they were registered in
+	entry := models.AuditEntry{
+		Actor:      strings.ToLower(actor),
+		Action:     action,
+		EntityType: entityType,
+		Detail:     detail,
+		IP:         middleware.ClientIP(c),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		CreatedAt:  time.Now(),
+	}
+	if !entityID.IsZero() {
+		entry.EntityID = &entityID
+	}
+	entry.Before, entry.After = models.AuditChanges(before, after)
+
+	// The request context may be close to its deadline, so the entry gets its own
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := configs.AuditColl.InsertOne(ctx, entry); err != nil {
+		log.Printf("recordAudit: Failed to record %s of %s %s by %s: %v", action, entityType, entityID.Hex(), actor, err)
+	}
+}
+
+// GetAuditLogHandler lists audit entries, newest first. Filters: actor, action,
+// entityType, entityId, from and to (YYYY-MM-DD, inclusive, UTC).
+func GetAuditLogHandler(c *fiber.Ctx) error {
+	filter := bson.M{}
+	if actor := c.Query("actor"); actor != "" {
+		filter["actor"] = strings.ToLower(actor)
+	}
+	if action := c.Query("action"); action != "" {
+		filter["action"] = action
+	}
+	if entityType := c.Query("entityType"); entityType != "" {
+		filter["entityType"] = entityType
+	}
+	if entityID := c.Query("entityId"); entityID != "" {
+		objID, err := primitive.ObjectIDFromHex(entityID)
+		if err != nil {
+			log.Printf("GetAuditLogHandler: Invalid entity ID %s: %v", entityID, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid entity ID",
+			})
+		}
+		filter["entityId"] = objID
+	}
+
+	createdAt := bson.M{}
+	if from := c.Query("from"); from != "" {
+		day, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			log.Printf("GetAuditLogHandler: Invalid from date %s: %v", from, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "from must be a date like 2024-12-31",
+			})
+		}
+		createdAt["$gte"] = day
+	}
+	if to := c.Query("to"); to != "" {
+		day, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			log.Printf("GetAuditLogHandler: Invalid to date %s: %v", to, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "to must be a date like 2024-12-31",
+			})
+		}
+		createdAt["$lt"] = day.AddDate(0, 0, 1)
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > maxAuditLimit {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := configs.AuditColl.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("GetAuditLogHandler: Failed to count audit entries: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit log",
+		})
+	}
+
+	cursor, err := configs.AuditColl.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		log.Printf("GetAuditLogHandler: Failed to fetch audit entries: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit log",
+		})
+	}
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		log.Printf("GetAuditLogHandler: Failed to process audit entries: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process audit log",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Audit log fetched successfully",
+		"data":    entries,
+		"count":   len(entries),
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -62,13 +63,15 @@ func CreateUserHandler(c *fiber.Ctx) error {
 		Password: string(hashedPassword),
 	}
 
-	_, err = configs.UsersColl.InsertOne(ctx, user)
+	result, err := configs.UsersColl.InsertOne(ctx, user)
 	if err != nil {
 		log.Printf("CreateUserHandler: Failed to create user with email %s: %v", req.Email, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create user",
 		})
 	}
+	userID := result.InsertedID.(primitive.ObjectID)
+	recordAudit(c, user.Email, models.AuditActionCreate, models.AuditEntityUser, userID, nil, auditSnapshot(ctx, configs.UsersColl, userID), "")
 
 	log.Printf("CreateUserHandler: User created successfully with email %s", req.Email)
 	return c.JSON(fiber.Map{
@@ -131,6 +134,7 @@ func LoginHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	recordAudit(c, user.Email, models.AuditActionLogin, models.AuditEntityUser, user.ID, nil, nil, "")
 	log.Printf("LoginHandler: User logged in successfully with email %s", req.Email)
 	return c.JSON(fiber.Map{
 		"token": tokenString,
@@ -229,6 +233,8 @@ func ResetPasswordHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	// The summaries never include the password, so only the fact of the reset is recorded
+	recordAudit(c, tokenEmail, models.AuditActionUpdate, models.AuditEntityUser, user.ID, nil, nil, "Password reset")
 	log.Printf("ResetPasswordHandler: Password reset successfully for email %s", req.Email)
 	return c.JSON(fiber.Map{
 		"message": "Password reset successfully",
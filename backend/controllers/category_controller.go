@@ -4,7 +4,9 @@ import (
 	"backend/configs"
 	"backend/models"
 	"context"
+	"fmt"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,10 +15,155 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// nameCollation compares category names ignoring letter case only, so "logo" and "Logo"
+// count as duplicates while Thai words that differ by a tone mark stay distinct.
+var nameCollation = &options.Collation{Locale: "th", Strength: 2}
+
+// CategoryRequest leaves landing page fields that are omitted (nil) unchanged on update.
 type CategoryRequest struct {
-	NameCategory string `json:"nameCategory"`
+	NameCategory   string  `json:"nameCategory"`
+	Slug           string  `json:"slug,omitempty"`
+	ParentID       string  `json:"parentId,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	HeroMediaUrl   *string `json:"heroMediaUrl,omitempty"`
+	CoverImageUrl  *string `json:"coverImageUrl,omitempty"`
+	Icon           *string `json:"icon,omitempty"`
+	SeoTitle       *string `json:"seoTitle,omitempty"`
+	SeoDescription *string `json:"seoDescription,omitempty"`
+	DisplayOrder   *int    `json:"displayOrder,omitempty"`
+	Visible        *bool   `json:"visible,omitempty"`
+	Version        *int64  `json:"version,omitempty"`
+}
+
+// applyMetadata copies the landing page fields present in the request onto category
+// and returns them as a $set document.
+func (req CategoryRequest) applyMetadata(category *models.Category) bson.M {
+	set := bson.M{}
+	if req.Description != nil {
+		category.Description = models.NormalizeMultiline(*req.Description)
+		set["description"] = category.Description
+	}
+	if req.HeroMediaUrl != nil {
+		category.HeroMediaUrl = strings.TrimSpace(*req.HeroMediaUrl)
+		set["heroMediaUrl"] = category.HeroMediaUrl
+	}
+	if req.CoverImageUrl != nil {
+		category.CoverImageUrl = strings.TrimSpace(*req.CoverImageUrl)
+		set["coverImageUrl"] = category.CoverImageUrl
+	}
+	if req.Icon != nil {
+		category.Icon = strings.TrimSpace(*req.Icon)
+		set["icon"] = category.Icon
+	}
+	if req.SeoTitle != nil {
+		category.SeoTitle = models.NormalizeText(*req.SeoTitle)
+		set["seoTitle"] = category.SeoTitle
+	}
+	if req.SeoDescription != nil {
+		category.SeoDescription = models.NormalizeText(*req.SeoDescription)
+		set["seoDescription"] = category.SeoDescription
+	}
+	if req.DisplayOrder != nil {
+		category.DisplayOrder = *req.DisplayOrder
+		set["displayOrder"] = category.DisplayOrder
+	}
+	if req.Visible != nil {
+		category.Visible = *req.Visible
+		set["visible"] = category.Visible
+	}
+	return set
+}
+
+// categorySort lists categories the way the user site navigation shows them.
+var categorySort = bson.D{
+	{Key: "displayOrder", Value: 1},
+	{Key: "nameCategory", Value: 1},
+}
+
+// siblingFilter matches categories sharing the given parent; nil matches top-level categories.
+func siblingFilter(parentID *primitive.ObjectID) interface{} {
+	if parentID == nil {
+		return nil
+	}
+	return *parentID
+}
+
+// findCategoryBySlug resolves the category URL param. Params are decoded and slugified
+// first so Thai slugs and links built from display names ("Visual Design") keep working.
+// moved is true when the param is a slug the category had before a rename.
+func findCategoryBySlug(ctx context.Context, param string, category *models.Category) (moved bool, err error) {
+	slug := models.SlugFromParam(param)
+	if slug == "" {
+		return false, mongo.ErrNoDocuments
+	}
+
+	err = configs.CategoriesColl.FindOne(ctx, bson.M{"slug": slug}).Decode(category)
+	if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	err = configs.CategoriesColl.FindOne(ctx, bson.M{"previousSlugs": slug}).Decode(category)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// redirectToCategorySlug sends a permanent redirect to the same route with the category's current slug.
+func redirectToCategorySlug(c *fiber.Ctx, category *models.Category) error {
+	target := categoryRedirectTarget(c.Route().Path, c.Params, category.Slug, string(c.Request().URI().QueryString()))
+	return c.Redirect(target, fiber.StatusMovedPermanently)
+}
+
+// categoryRedirectTarget rebuilds a URL from its route pattern: literal segments are kept,
+// the :category param becomes the escaped slug and other params keep the value they were
+// requested with. Building from the route rather than editing the URL means a slug that
+// also appears elsewhere in the path (e.g. "projects") cannot be replaced by mistake.
+func categoryRedirectTarget(routePath string, param func(key string, defaultValue ...string) string, slug, query string) string {
+	var target strings.Builder
+	for _, segment := range strings.Split(strings.Trim(routePath, "/"), "/") {
+		value := segment
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?")
+			if i := strings.IndexByte(name, '<'); i >= 0 {
+				name = name[:i]
+			}
+			if name == "category" {
+				value = url.PathEscape(slug)
+			} else {
+				value = param(name)
+			}
+		case segment == "*" || segment == "+":
+			value = param(segment)
+		}
+		if value == "" {
+			continue
+		}
+		target.WriteString("/")
+		target.WriteString(value)
+	}
+	if target.Len() == 0 {
+		target.WriteString("/")
+	}
+	if query != "" {
+		target.WriteString("?")
+		target.WriteString(query)
+	}
+	return target.String()
+}
+
+// releaseSlug drops a slug from other categories' redirect lists once it is taken as a current slug.
+func releaseSlug(ctx context.Context, slug string, owner primitive.ObjectID) error {
+	_, err := configs.CategoriesColl.UpdateMany(ctx,
+		bson.M{"previousSlugs": slug, "_id": bson.M{"$ne": owner}},
+		bson.M{"$pull": bson.M{"previousSlugs": slug}},
+	)
+	return err
 }
 
 func AddCategoryHandler(c *fiber.Ctx) error {
@@ -53,15 +200,55 @@ func AddCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Capitalize nameCategory
-	nameCategory := strings.Title(strings.ToLower(req.NameCategory))
+	// Normalize nameCategory; letter case is kept as typed
+	nameCategory := models.NormalizeText(req.NameCategory)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	var parentID *primitive.ObjectID
+	var parent models.Category
+	if req.ParentID != "" {
+		objID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			log.Printf("AddCategoryHandler: Invalid parent ID %s: %v", req.ParentID, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid parent category ID",
+			})
+		}
+		if err := configs.CategoriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&parent); err != nil {
+			log.Printf("AddCategoryHandler: Parent category %s not found: %v", req.ParentID, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Parent category not found",
+			})
+		}
+		parentID = &objID
+	}
+
+	slug := models.Slugify(nameCategory)
+	// Subcategories are prefixed with the parent slug so "Restaurant" can exist under several parents
+	if parentID != nil && slug != "" && parent.Slug != "" {
+		slug = parent.Slug + "-" + slug
+	}
+	if req.Slug != "" {
+		if !models.IsSlugValid(req.Slug) {
+			log.Printf("AddCategoryHandler: Invalid slug %s", req.Slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Slug must be lowercase letters, digits and single dashes",
+			})
+		}
+		slug = req.Slug
+	}
+	if slug == "" {
+		log.Printf("AddCategoryHandler: Cannot derive slug from name %s", nameCategory)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Slug is required when the name has no letters or digits",
+		})
+	}
+
 	// Check if category exists
 	var existingCategory models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": nameCategory}).Decode(&existingCategory)
+	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": nameCategory, "parentId": siblingFilter(parentID)}, options.FindOne().SetCollation(nameCollation)).Decode(&existingCategory)
 	if err == nil {
 		log.Printf("AddCategoryHandler: Category %s already exists", nameCategory)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -69,8 +256,27 @@ func AddCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	err = configs.CategoriesColl.FindOne(ctx, bson.M{"slug": slug}).Decode(&existingCategory)
+	if err == nil {
+		log.Printf("AddCategoryHandler: Slug %s already used by category %s", slug, existingCategory.NameCategory)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category slug already exists",
+		})
+	}
+
 	category := models.Category{
 		NameCategory: nameCategory,
+		Slug:         slug,
+		ParentID:     parentID,
+		Visible:      true,
+		Version:      1,
+	}
+	req.applyMetadata(&category)
+	if err := category.ValidateMetadata(); err != nil {
+		log.Printf("AddCategoryHandler: Invalid metadata for category %s: %v", nameCategory, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
 	result, err := configs.CategoriesColl.InsertOne(ctx, category)
@@ -82,6 +288,14 @@ func AddCategoryHandler(c *fiber.Ctx) error {
 	}
 
 	category.ID = result.InsertedID.(primitive.ObjectID)
+	if err := releaseSlug(ctx, slug, category.ID); err != nil {
+		log.Printf("AddCategoryHandler: Failed to release slug %s from previous owners: %v", slug, err)
+	}
+
+	recordAudit(c, userEmail, models.AuditActionCreate, models.AuditEntityCategory, category.ID, nil, auditSummaryOf(category), "")
+	publishEvent(models.EventCategoryCreated, userEmail, category.ID, category.ID, category)
+
+	c.Set(fiber.HeaderETag, formatETag(category.Version))
 	log.Printf("AddCategoryHandler: Category %s created successfully by user %s", nameCategory, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Category created successfully",
@@ -90,7 +304,7 @@ func AddCategoryHandler(c *fiber.Ctx) error {
 }
 
 func GetCategoriesHandler(c *fiber.Ctx) error {
-	// Get user email from JWT token (optional, since this endpoint requires auth)
+	// Get user email from JWT token (optional; signed-in admins also see hidden categories)
 	claims, ok := c.Locals("user").(jwt.MapClaims)
 	userEmail := "anonymous"
 	if ok {
@@ -104,7 +318,7 @@ func GetCategoriesHandler(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := configs.CategoriesColl.Find(ctx, bson.M{})
+	cursor, err := configs.CategoriesColl.Find(ctx, bson.M{}, options.Find().SetSort(categorySort))
 	if err != nil {
 		log.Printf("GetCategoriesHandler: Failed to fetch categories: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -121,6 +335,15 @@ func GetCategoriesHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	if userEmail == "anonymous" {
+		categories = models.VisibleCategories(categories)
+	}
+
+	locale := requestLocale(c)
+	for i := range categories {
+		categories[i] = categories[i].Localized(locale)
+	}
+
 	log.Printf("GetCategoriesHandler: Retrieved %d categories by user %s", len(categories), userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Categories retrieved successfully",
@@ -171,8 +394,16 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Capitalize nameCategory
-	nameCategory := strings.Title(strings.ToLower(req.NameCategory))
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateCategoryHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	// Normalize nameCategory; letter case is kept as typed
+	nameCategory := models.NormalizeText(req.NameCategory)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -197,7 +428,7 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 
 	// Check if new nameCategory already exists (excluding current category)
 	var duplicateCategory models.Category
-	err = configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": nameCategory, "_id": bson.M{"$ne": objID}}).Decode(&duplicateCategory)
+	err = configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": nameCategory, "parentId": siblingFilter(existingCategory.ParentID), "_id": bson.M{"$ne": objID}}, options.FindOne().SetCollation(nameCollation)).Decode(&duplicateCategory)
 	if err == nil {
 		log.Printf("UpdateCategoryHandler: Category name %s already exists", nameCategory)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -205,15 +436,55 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	set := req.applyMetadata(&existingCategory)
+	if err := existingCategory.ValidateMetadata(); err != nil {
+		log.Printf("UpdateCategoryHandler: Invalid metadata for category ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	set["nameCategory"] = nameCategory
+	set["updatedAt"] = time.Now()
+
+	// The slug only changes when explicitly requested; the old one keeps redirecting
+	slugChanged := req.Slug != "" && req.Slug != existingCategory.Slug
+	if slugChanged {
+		if !models.IsSlugValid(req.Slug) {
+			log.Printf("UpdateCategoryHandler: Invalid slug %s", req.Slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Slug must be lowercase letters, digits and single dashes",
+			})
+		}
+
+		err = configs.CategoriesColl.FindOne(ctx, bson.M{"slug": req.Slug, "_id": bson.M{"$ne": objID}}).Decode(&duplicateCategory)
+		if err == nil {
+			log.Printf("UpdateCategoryHandler: Slug %s already used by category %s", req.Slug, duplicateCategory.NameCategory)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category slug already exists",
+			})
+		}
+
+		previousSlugs := []string{}
+		for _, previous := range existingCategory.PreviousSlugs {
+			if previous != req.Slug {
+				previousSlugs = append(previousSlugs, previous)
+			}
+		}
+		if existingCategory.Slug != "" {
+			previousSlugs = append(previousSlugs, existingCategory.Slug)
+		}
+		set["slug"] = req.Slug
+		set["previousSlugs"] = previousSlugs
+	}
+
 	// Update category
 	update := bson.M{
-		"$set": bson.M{
-			"nameCategory": nameCategory,
-			"updatedAt":    time.Now(),
-		},
+		"$set": set,
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := configs.CategoriesColl.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	before := auditSnapshot(ctx, configs.CategoriesColl, objID)
+	result, err := configs.CategoriesColl.UpdateOne(ctx, withVersion(bson.M{"_id": objID}, version, anyVersion), update)
 	if err != nil {
 		log.Printf("UpdateCategoryHandler: Failed to update category ID %s: %v", id, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -222,12 +493,27 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 	}
 
 	if result.MatchedCount == 0 {
+		current, err := currentVersion(ctx, configs.CategoriesColl, bson.M{"_id": objID})
+		if err == nil {
+			log.Printf("UpdateCategoryHandler: Stale write for category ID %s, expected version %d, current %d", id, version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Category was modified by someone else",
+				"version": current,
+			})
+		}
 		log.Printf("UpdateCategoryHandler: No category matched for ID %s", id)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Category not found",
 		})
 	}
 
+	if slugChanged {
+		if err := releaseSlug(ctx, req.Slug, objID); err != nil {
+			log.Printf("UpdateCategoryHandler: Failed to release slug %s from previous owners: %v", req.Slug, err)
+		}
+	}
+
 	// Fetch the updated category
 	var updatedCategory models.Category
 	err = configs.CategoriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&updatedCategory)
@@ -238,6 +524,10 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityCategory, objID, before, auditSummaryOf(updatedCategory), "")
+	publishEvent(models.EventCategoryUpdated, userEmail, objID, objID, updatedCategory)
+
+	c.Set(fiber.HeaderETag, formatETag(updatedCategory.Version))
 	log.Printf("UpdateCategoryHandler: Category %s updated successfully by user %s", nameCategory, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Category updated successfully",
@@ -245,6 +535,188 @@ func UpdateCategoryHandler(c *fiber.Ctx) error {
 	})
 }
 
+// Category deletion strategies, chosen with the reassignTo and cascade query parameters.
+const (
+	categoryDeleteEmpty    = "empty"
+	categoryDeleteReassign = "reassign"
+	categoryDeleteCascade  = "cascade"
+)
+
+type CategoryDeletionItem struct {
+	ID    string `json:"_id"`
+	Title string `json:"title"`
+}
+
+// CategoryDeletionSlug is a project whose slug is already used in the reassign target; it
+// moves under NewSlug.
+type CategoryDeletionSlug struct {
+	ID      string `json:"_id"`
+	Slug    string `json:"slug"`
+	NewSlug string `json:"newSlug"`
+}
+
+type CategoryDeletionFile struct {
+	ID       string `json:"_id"`
+	Filename string `json:"filename,omitempty"`
+	Size     int64  `json:"size"`
+}
+
+// CategoryDeletionReport lists everything a category deletion touches. It is returned
+// by dry runs, by refused deletions and after a successful delete.
+type CategoryDeletionReport struct {
+	Strategy      string                 `json:"strategy"`
+	ReassignTo    string                 `json:"reassignTo,omitempty"`
+	Allowed       bool                   `json:"allowed"`
+	Projects      []CategoryDeletionItem `json:"projects"`
+	ServiceSteps  []CategoryDeletionItem `json:"serviceSteps"`
+	Subcategories []CategoryDeletionItem `json:"subcategories"`
+	Files         []CategoryDeletionFile `json:"files,omitempty"`
+	NameConflicts []string               `json:"nameConflicts,omitempty"`
+	SlugConflicts []CategoryDeletionSlug `json:"slugConflicts,omitempty"`
+	FailedFiles   []string               `json:"failedFiles,omitempty"`
+}
+
+// buildCategoryDeletionReport collects the projects, service steps and subcategories of a
+// category. Uploaded media is listed only for cascade deletes, the one strategy that removes it.
+// Subcategories whose names are already taken under the category's parent are listed as
+// NameConflicts, as moving them up would break the unique sibling names. For reassign, projects
+// whose slugs are taken in the target are listed as SlugConflicts with the slug they get there.
+func buildCategoryDeletionReport(ctx context.Context, category *models.Category, strategy string, targetID primitive.ObjectID) (*CategoryDeletionReport, error) {
+	report := &CategoryDeletionReport{
+		Strategy:      strategy,
+		Projects:      []CategoryDeletionItem{},
+		ServiceSteps:  []CategoryDeletionItem{},
+		Subcategories: []CategoryDeletionItem{},
+	}
+
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(projectSort))
+	if err != nil {
+		return nil, err
+	}
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+
+	filesColl := configs.Client.Database("ProjectsDB").Collection("FilesColl")
+	// Slugs the moved projects take in the target, as they move one after another
+	taken := map[string]bool{}
+	for _, project := range projects {
+		report.Projects = append(report.Projects, CategoryDeletionItem{ID: project.ID.Hex(), Title: project.Title})
+		if strategy == categoryDeleteReassign && project.Slug != "" {
+			slug, err := reassignedProjectSlug(ctx, targetID, project.Slug, taken)
+			if err != nil {
+				return nil, err
+			}
+			taken[slug] = true
+			if slug != project.Slug {
+				report.SlugConflicts = append(report.SlugConflicts, CategoryDeletionSlug{ID: project.ID.Hex(), Slug: project.Slug, NewSlug: slug})
+			}
+		}
+		if strategy != categoryDeleteCascade {
+			continue
+		}
+		for _, fileUrl := range []string{project.ImageUrl, project.VideoUrl} {
+			fileID, ok := mediaFileID(fileUrl)
+			if !ok {
+				continue
+			}
+			file := CategoryDeletionFile{ID: fileID.Hex()}
+			var meta struct {
+				Filename string `bson:"filename"`
+				Size     int64  `bson:"size"`
+			}
+			if err := filesColl.FindOne(ctx, bson.M{"_id": fileID}).Decode(&meta); err == nil {
+				file.Filename = meta.Filename
+				file.Size = meta.Size
+			}
+			report.Files = append(report.Files, file)
+		}
+	}
+
+	cursor, err = configs.ServiceStepsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(serviceStepSort))
+	if err != nil {
+		return nil, err
+	}
+	var serviceSteps []models.ServiceStep
+	if err := cursor.All(ctx, &serviceSteps); err != nil {
+		return nil, err
+	}
+	for _, step := range serviceSteps {
+		report.ServiceSteps = append(report.ServiceSteps, CategoryDeletionItem{ID: step.ID.Hex(), Title: step.Title})
+	}
+
+	cursor, err = configs.CategoriesColl.Find(ctx, bson.M{"parentId": category.ID})
+	if err != nil {
+		return nil, err
+	}
+	var subcategories []models.Category
+	if err := cursor.All(ctx, &subcategories); err != nil {
+		return nil, err
+	}
+	for _, subcategory := range subcategories {
+		report.Subcategories = append(report.Subcategories, CategoryDeletionItem{ID: subcategory.ID.Hex(), Title: subcategory.NameCategory})
+
+		count, err := configs.CategoriesColl.CountDocuments(ctx,
+			bson.M{"nameCategory": subcategory.NameCategory, "parentId": siblingFilter(category.ParentID), "_id": bson.M{"$ne": category.ID}},
+			options.Count().SetCollation(nameCollation),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			report.NameConflicts = append(report.NameConflicts, subcategory.NameCategory)
+		}
+	}
+
+	hasContent := len(report.Projects) > 0 || len(report.ServiceSteps) > 0
+	report.Allowed = (!hasContent || strategy != categoryDeleteEmpty) && len(report.NameConflicts) == 0
+	return report, nil
+}
+
+// reassignedProjectSlug predicts the slug relocateProject gives a project moving into the
+// target category, skipping slugs taken by projects that move before it.
+func reassignedProjectSlug(ctx context.Context, targetID primitive.ObjectID, base string, taken map[string]bool) (string, error) {
+	slug := base
+	for n := 2; ; n++ {
+		if !taken[slug] {
+			count, err := configs.ProjectsColl.CountDocuments(ctx, bson.M{"category_id": targetID, "slug": slug})
+			if err != nil {
+				return "", err
+			}
+			if count == 0 {
+				return slug, nil
+			}
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// nextSequence returns the value after the highest field value among a category's
+// documents, or first when the category has none.
+func nextSequence(ctx context.Context, coll *mongo.Collection, categoryID primitive.ObjectID, field string, first int) (int, error) {
+	var doc bson.M
+	err := coll.FindOne(ctx,
+		bson.M{"category_id": categoryID, field: bson.M{"$exists": true}},
+		options.FindOne().SetSort(bson.D{{Key: field, Value: -1}}).SetProjection(bson.M{field: 1}),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return first, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	switch v := doc[field].(type) {
+	case int32:
+		return int(v) + 1, nil
+	case int64:
+		return int(v) + 1, nil
+	case float64:
+		return int(v) + 1, nil
+	}
+	return first, nil
+}
+
 func DeleteCategoryHandler(c *fiber.Ctx) error {
 	// Get user email from JWT token
 	claims, ok := c.Locals("user").(jwt.MapClaims)
@@ -270,7 +742,18 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	log.Printf("DeleteCategoryHandler: User %s requested to delete category ID %s", userEmail, id)
+	reassignTo := c.Query("reassignTo")
+	cascade := c.QueryBool("cascade")
+	dryRun := c.QueryBool("dryRun")
+
+	log.Printf("DeleteCategoryHandler: User %s requested to delete category ID %s (reassignTo=%q cascade=%t dryRun=%t)", userEmail, id, reassignTo, cascade, dryRun)
+
+	if reassignTo != "" && cascade {
+		log.Printf("DeleteCategoryHandler: Both reassignTo and cascade given")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Use either reassignTo or cascade, not both",
+		})
+	}
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -281,9 +764,79 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 	}
 
 	// Increase timeout to handle large deletions
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	var category models.Category
+	if err := configs.CategoriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&category); err != nil {
+		log.Printf("DeleteCategoryHandler: Category ID %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	strategy := categoryDeleteEmpty
+	var targetID primitive.ObjectID
+	if reassignTo != "" {
+		strategy = categoryDeleteReassign
+		targetID, err = primitive.ObjectIDFromHex(reassignTo)
+		if err != nil {
+			log.Printf("DeleteCategoryHandler: Invalid reassignTo ID %s: %v", reassignTo, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid reassignTo category ID",
+			})
+		}
+		if targetID == objID {
+			log.Printf("DeleteCategoryHandler: Cannot reassign category %s to itself", id)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Cannot reassign content to the category being deleted",
+			})
+		}
+		count, err := configs.CategoriesColl.CountDocuments(ctx, bson.M{"_id": targetID})
+		if err != nil || count == 0 {
+			log.Printf("DeleteCategoryHandler: Target category %s not found: %v", reassignTo, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Target category not found",
+			})
+		}
+	} else if cascade {
+		strategy = categoryDeleteCascade
+	}
+
+	report, err := buildCategoryDeletionReport(ctx, &category, strategy, targetID)
+	if err != nil {
+		log.Printf("DeleteCategoryHandler: Failed to collect content of category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to collect category content",
+		})
+	}
+	if strategy == categoryDeleteReassign {
+		report.ReassignTo = reassignTo
+	}
+
+	if dryRun {
+		log.Printf("DeleteCategoryHandler: Dry run for category ID %s: %d projects, %d service steps, %d files", id, len(report.Projects), len(report.ServiceSteps), len(report.Files))
+		return c.JSON(fiber.Map{
+			"message": "Dry run, nothing was deleted",
+			"data":    report,
+		})
+	}
+
+	if len(report.NameConflicts) > 0 {
+		log.Printf("DeleteCategoryHandler: Subcategories of category ID %s clash with names under its parent: %v", id, report.NameConflicts)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Subcategories have the same names as categories they would move next to: " + strings.Join(report.NameConflicts, ", "),
+			"data":  report,
+		})
+	}
+	if !report.Allowed {
+		log.Printf("DeleteCategoryHandler: Category ID %s has content and no strategy was given", id)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Category has projects or service steps; pass reassignTo=<categoryId> or cascade=true",
+			"data":  report,
+		})
+	}
+
 	// Start a MongoDB session for transaction
 	session, err := configs.Client.StartSession()
 	if err != nil {
@@ -294,6 +847,13 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 	}
 	defer session.EndSession(ctx)
 
+	errContentAdded := fmt.Errorf("content was added to the category")
+	errSlugTaken := fmt.Errorf("a project slug was taken in the target category")
+	errNameTaken := fmt.Errorf("a subcategory name was taken under the parent")
+
+	var moved []models.Project
+	var snapshots []bson.M
+
 	// Execute transaction
 	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
 		// Check if category exists
@@ -304,21 +864,95 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 			return nil, err
 		}
 
-		// Delete associated projects
-		projectsResult, err := configs.ProjectsColl.DeleteMany(sessionContext, bson.M{"category_id": objID})
-		if err != nil {
-			log.Printf("DeleteCategoryHandler: Failed to delete projects for category ID %s: %v", id, err)
-			return nil, err
-		}
-		log.Printf("DeleteCategoryHandler: Deleted %d projects for category ID %s", projectsResult.DeletedCount, id)
+		switch strategy {
+		case categoryDeleteEmpty:
+			// Content may have been added since the check above
+			count, err := configs.ProjectsColl.CountDocuments(sessionContext, bson.M{"category_id": objID})
+			if err != nil {
+				return nil, err
+			}
+			steps, err := configs.ServiceStepsColl.CountDocuments(sessionContext, bson.M{"category_id": objID})
+			if err != nil {
+				return nil, err
+			}
+			if count+steps > 0 {
+				return nil, errContentAdded
+			}
 
-		// Delete associated service steps
-		serviceStepsResult, err := configs.ServiceStepsColl.DeleteMany(sessionContext, bson.M{"category_id": objID})
-		if err != nil {
-			log.Printf("DeleteCategoryHandler: Failed to delete service steps for category ID %s: %v", id, err)
-			return nil, err
+		case categoryDeleteReassign:
+			// Moved projects and steps keep their order and go after the target's own
+			position, err := nextSequence(sessionContext, configs.ProjectsColl, targetID, "position", 0)
+			if err != nil {
+				return nil, err
+			}
+			cursor, err := configs.ProjectsColl.Find(sessionContext, bson.M{"category_id": objID}, options.Find().SetSort(projectSort))
+			if err != nil {
+				return nil, err
+			}
+			moved = nil
+			if err := cursor.All(sessionContext, &moved); err != nil {
+				return nil, err
+			}
+			// relocateProject gives projects whose slug is taken in the target a suffixed one
+			now := time.Now()
+			snapshots = make([]bson.M, len(moved))
+			for i := range moved {
+				snapshots[i], err = relocateProject(sessionContext, &moved[i], targetID, position+i, now)
+				if err != nil {
+					log.Printf("DeleteCategoryHandler: Failed to move project %s to category %s: %v", moved[i].ID.Hex(), reassignTo, err)
+					if mongo.IsDuplicateKeyError(err) {
+						return nil, errSlugTaken
+					}
+					return nil, err
+				}
+			}
+			log.Printf("DeleteCategoryHandler: Moved %d projects from category ID %s to %s", len(moved), id, reassignTo)
+
+			if err := lockServiceSteps(sessionContext, targetID); err != nil {
+				return nil, err
+			}
+			stepNumber, err := nextSequence(sessionContext, configs.ServiceStepsColl, targetID, "stepNumber", 1)
+			if err != nil {
+				return nil, err
+			}
+			cursor, err = configs.ServiceStepsColl.Find(sessionContext, bson.M{"category_id": objID}, options.Find().SetSort(serviceStepSort).SetProjection(bson.M{"_id": 1}))
+			if err != nil {
+				return nil, err
+			}
+			var serviceSteps []models.ServiceStep
+			if err := cursor.All(sessionContext, &serviceSteps); err != nil {
+				return nil, err
+			}
+			for _, step := range serviceSteps {
+				_, err := configs.ServiceStepsColl.UpdateOne(sessionContext,
+					bson.M{"_id": step.ID},
+					bson.M{"$set": bson.M{"category_id": targetID, "stepNumber": stepNumber, "updatedAt": time.Now()}, "$inc": bson.M{"version": 1}},
+				)
+				if err != nil {
+					log.Printf("DeleteCategoryHandler: Failed to move service step %s to category %s: %v", step.ID.Hex(), reassignTo, err)
+					return nil, err
+				}
+				stepNumber++
+			}
+			log.Printf("DeleteCategoryHandler: Moved %d service steps from category ID %s to %s", len(serviceSteps), id, reassignTo)
+
+		case categoryDeleteCascade:
+			// Delete associated projects
+			projectsResult, err := configs.ProjectsColl.DeleteMany(sessionContext, bson.M{"category_id": objID})
+			if err != nil {
+				log.Printf("DeleteCategoryHandler: Failed to delete projects for category ID %s: %v", id, err)
+				return nil, err
+			}
+			log.Printf("DeleteCategoryHandler: Deleted %d projects for category ID %s", projectsResult.DeletedCount, id)
+
+			// Delete associated service steps
+			serviceStepsResult, err := configs.ServiceStepsColl.DeleteMany(sessionContext, bson.M{"category_id": objID})
+			if err != nil {
+				log.Printf("DeleteCategoryHandler: Failed to delete service steps for category ID %s: %v", id, err)
+				return nil, err
+			}
+			log.Printf("DeleteCategoryHandler: Deleted %d service steps for category ID %s", serviceStepsResult.DeletedCount, id)
 		}
-		log.Printf("DeleteCategoryHandler: Deleted %d service steps for category ID %s", serviceStepsResult.DeletedCount, id)
 
 		// Delete the category
 		result, err := configs.CategoriesColl.DeleteOne(sessionContext, bson.M{"_id": objID})
@@ -332,6 +966,22 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 			return nil, mongo.ErrNoDocuments
 		}
 
+		// Subcategories move up to the deleted category's parent; this runs after the
+		// delete so a child named like the deleted category can take its place
+		childUpdate := bson.M{"$unset": bson.M{"parentId": ""}, "$inc": bson.M{"version": 1}}
+		if category.ParentID != nil {
+			childUpdate = bson.M{"$set": bson.M{"parentId": *category.ParentID}, "$inc": bson.M{"version": 1}}
+		}
+		childrenResult, err := configs.CategoriesColl.UpdateMany(sessionContext, bson.M{"parentId": objID}, childUpdate)
+		if err != nil {
+			log.Printf("DeleteCategoryHandler: Failed to move subcategories of category ID %s: %v", id, err)
+			if mongo.IsDuplicateKeyError(err) {
+				return nil, errNameTaken
+			}
+			return nil, err
+		}
+		log.Printf("DeleteCategoryHandler: Moved %d subcategories of category ID %s up one level", childrenResult.ModifiedCount, id)
+
 		return nil, nil
 	})
 
@@ -341,14 +991,68 @@ func DeleteCategoryHandler(c *fiber.Ctx) error {
 				"error": "Category not found",
 			})
 		}
+		if err == errNameTaken {
+			log.Printf("DeleteCategoryHandler: A subcategory name of category ID %s was taken during deletion", id)
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Subcategories have the same names as categories they would move next to",
+			})
+		}
+		if err == errSlugTaken {
+			log.Printf("DeleteCategoryHandler: A project slug was taken in category %s while moving projects of category ID %s", reassignTo, id)
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "A project slug was taken in the target category during the move, please try again",
+			})
+		}
+		if err == errProjectsChanged {
+			log.Printf("DeleteCategoryHandler: A project left category ID %s during deletion", id)
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Projects of the category were changed by someone else, please try again",
+			})
+		}
+		if err == errContentAdded {
+			log.Printf("DeleteCategoryHandler: Content was added to category ID %s during deletion", id)
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Category has projects or service steps; pass reassignTo=<categoryId> or cascade=true",
+			})
+		}
 		log.Printf("DeleteCategoryHandler: Transaction failed for category ID %s: %v", id, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete category and associated data",
 		})
 	}
 
-	log.Printf("DeleteCategoryHandler: Category ID %s and associated data deleted successfully by user %s", id, userEmail)
+	// Media is removed only after the documents referencing it are gone; a failure
+	// leaves an orphaned file, which is reported rather than undoing the delete
+	if strategy == categoryDeleteCascade && len(report.Files) > 0 {
+		bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+		if err != nil {
+			log.Printf("DeleteCategoryHandler: Failed to initialize GridFS: %v", err)
+			for _, file := range report.Files {
+				report.FailedFiles = append(report.FailedFiles, file.ID)
+			}
+		} else {
+			for _, file := range report.Files {
+				fileID, _ := primitive.ObjectIDFromHex(file.ID)
+				if err := deleteGridFSFileByID(ctx, bucket, fileID); err != nil {
+					report.FailedFiles = append(report.FailedFiles, file.ID)
+				}
+			}
+		}
+	}
+
+	for i, project := range moved {
+		if _, err := recordRevision(ctx, models.RevisionEntityProject, project.ID, snapshots[i], userEmail, nil); err != nil {
+			log.Printf("DeleteCategoryHandler: Failed to record revision for project %s: %v", project.ID.Hex(), err)
+		}
+	}
+
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityCategory, objID, auditSummaryOf(category), nil,
+		fmt.Sprintf("Strategy %s: %d projects, %d service steps, %d subcategories", strategy, len(report.Projects), len(report.ServiceSteps), len(report.Subcategories)))
+	publishEvent(models.EventCategoryDeleted, userEmail, objID, objID, report)
+
+	log.Printf("DeleteCategoryHandler: Category ID %s deleted with strategy %s by user %s", id, strategy, userEmail)
 	return c.JSON(fiber.Map{
-		"message": "Category and associated data deleted successfully",
+		"message": "Category deleted successfully",
+		"data":    report,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import "testing"
+
+func TestCategoryRedirectTarget(t *testing.T) {
+	params := map[string]string{
+		"category": "projects",
+		"idOrSlug": "%E0%B8%84%E0%B8%A3%E0%B8%B1%E0%B8%A7",
+		"id":       "64b7f0c2a1b2c3d4e5f60718",
+	}
+	param := func(key string, defaultValue ...string) string { return params[key] }
+
+	tests := []struct {
+		route, slug, query, want string
+	}{
+		{"/projects/:category", "web-design", "", "/projects/web-design"},
+		{"/projects/:category/:idOrSlug", "web-design", "lang=en", "/projects/web-design/%E0%B8%84%E0%B8%A3%E0%B8%B1%E0%B8%A7?lang=en"},
+		{"/servicesteps/:category/service-steps", "web-design", "", "/servicesteps/web-design/service-steps"},
+		{"/og/:category/:id", "ออกแบบ", "", "/og/%E0%B8%AD%E0%B8%AD%E0%B8%81%E0%B9%81%E0%B8%9A%E0%B8%9A/64b7f0c2a1b2c3d4e5f60718"},
+		{"/feeds/:category/:page?", "web-design", "", "/feeds/web-design"},
+	}
+	for _, tt := range tests {
+		if got := categoryRedirectTarget(tt.route, param, tt.slug, tt.query); got != tt.want {
+			t.Errorf("categoryRedirectTarget(%q) = %q; want %q", tt.route, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryMoveRequest struct {
+	// ParentID is the new parent; empty moves the category to the top level
+	ParentID string `json:"parentId"`
+	Version  *int64 `json:"version,omitempty"`
+}
+
+// loadAllCategories reads the whole category collection, which is small enough to walk in memory.
+func loadAllCategories(ctx context.Context) ([]models.Category, error) {
+	cursor, err := configs.CategoriesColl.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []models.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// categoryIDsWithDescendants returns the category's ID, plus all its subcategory IDs when
+// requested. Hidden subcategories are skipped unless includeHidden is set.
+func categoryIDsWithDescendants(ctx context.Context, category *models.Category, includeDescendants, includeHidden bool) ([]primitive.ObjectID, error) {
+	if !includeDescendants {
+		return []primitive.ObjectID{category.ID}, nil
+	}
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !includeHidden {
+		categories = models.VisibleCategories(categories)
+	}
+	return models.CategoryDescendantIDs(categories, category.ID), nil
+}
+
+// isSignedIn reports whether OptionalAuthMiddleware found a valid token on the request.
+func isSignedIn(c *fiber.Ctx) bool {
+	_, ok := c.Locals("user").(jwt.MapClaims)
+	return ok
+}
+
+func GetCategoryTreeHandler(c *fiber.Ctx) error {
+	log.Printf("GetCategoryTreeHandler: Request to fetch category tree")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		log.Printf("GetCategoryTreeHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch categories",
+		})
+	}
+
+	if !isSignedIn(c) {
+		categories = models.VisibleCategories(categories)
+	}
+
+	locale := requestLocale(c)
+	for i := range categories {
+		categories[i] = categories[i].Localized(locale)
+	}
+
+	tree := models.BuildCategoryTree(categories)
+
+	log.Printf("GetCategoryTreeHandler: Retrieved %d categories in %d top-level trees", len(categories), len(tree))
+	return c.JSON(fiber.Map{
+		"message": "Category tree retrieved successfully",
+		"data":    tree,
+		"count":   len(categories),
+	})
+}
+
+func MoveCategoryHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("MoveCategoryHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("MoveCategoryHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	log.Printf("MoveCategoryHandler: User %s requested to move category ID %s", userEmail, id)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("MoveCategoryHandler: Invalid category ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	var req CategoryMoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("MoveCategoryHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("MoveCategoryHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	var parentID *primitive.ObjectID
+	if req.ParentID != "" {
+		parentObjID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			log.Printf("MoveCategoryHandler: Invalid parent ID %s: %v", req.ParentID, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid parent category ID",
+			})
+		}
+		parentID = &parentObjID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		log.Printf("MoveCategoryHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch categories",
+		})
+	}
+
+	var category *models.Category
+	parentFound := parentID == nil
+	for i := range categories {
+		if categories[i].ID == objID {
+			category = &categories[i]
+		}
+		if parentID != nil && categories[i].ID == *parentID {
+			parentFound = true
+		}
+	}
+	if category == nil {
+		log.Printf("MoveCategoryHandler: Category ID %s not found", id)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	if !parentFound {
+		log.Printf("MoveCategoryHandler: Parent category %s not found", req.ParentID)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parent category not found",
+		})
+	}
+	if parentID != nil && models.CategoryParentCreatesCycle(categories, objID, *parentID) {
+		log.Printf("MoveCategoryHandler: Moving category %s under %s would create a cycle", id, req.ParentID)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "A category cannot be moved under itself or one of its subcategories",
+		})
+	}
+
+	// Names stay unique among the new siblings
+	var duplicateCategory models.Category
+	err = configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": category.NameCategory, "parentId": siblingFilter(parentID), "_id": bson.M{"$ne": objID}}, options.FindOne().SetCollation(nameCollation)).Decode(&duplicateCategory)
+	if err == nil {
+		log.Printf("MoveCategoryHandler: Category name %s already exists under the new parent", category.NameCategory)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category name already exists under the new parent",
+		})
+	}
+
+	update := bson.M{
+		"$set": bson.M{"updatedAt": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+	if parentID != nil {
+		update["$set"].(bson.M)["parentId"] = *parentID
+	} else {
+		update["$unset"] = bson.M{"parentId": ""}
+	}
+
+	before := auditSnapshot(ctx, configs.CategoriesColl, objID)
+	result, err := configs.CategoriesColl.UpdateOne(ctx, withVersion(bson.M{"_id": objID}, version, anyVersion), update)
+	if err != nil {
+		log.Printf("MoveCategoryHandler: Failed to move category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to move category",
+		})
+	}
+	if result.MatchedCount == 0 {
+		current, err := currentVersion(ctx, configs.CategoriesColl, bson.M{"_id": objID})
+		if err == nil {
+			log.Printf("MoveCategoryHandler: Stale write for category ID %s, expected version %d, current %d", id, version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Category was modified by someone else",
+				"version": current,
+			})
+		}
+		log.Printf("MoveCategoryHandler: No category matched for ID %s", id)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	var movedCategory models.Category
+	if err := configs.CategoriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&movedCategory); err != nil {
+		log.Printf("MoveCategoryHandler: Failed to fetch moved category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch moved category",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionMove, models.AuditEntityCategory, objID, before, auditSummaryOf(movedCategory), "")
+	publishEvent(models.EventCategoryUpdated, userEmail, objID, objID, movedCategory)
+
+	c.Set(fiber.HeaderETag, formatETag(movedCategory.Version))
+	log.Printf("MoveCategoryHandler: Category %s moved under %q with its subcategories by user %s", id, req.ParentID, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Category moved successfully",
+		"data":    movedCategory,
+	})
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// formatETag renders a document version as a strong ETag value.
+func formatETag(version int64) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// expectedVersion reads the version the client last saw from If-Match, falling back to the body's version field.
+// anyVersion is true for "If-Match: *". A non-zero status means the precondition is missing or malformed.
+func expectedVersion(c *fiber.Ctx, bodyVersion *int64) (version int64, anyVersion bool, status int, msg string) {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "*" {
+		return 0, true, 0, ""
+	}
+	if ifMatch != "" {
+		tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), "\"")
+		v, err := strconv.ParseInt(tag, 10, 64)
+		if err != nil || v < 0 {
+			return 0, false, fiber.StatusBadRequest, "Invalid If-Match header"
+		}
+		return v, false, 0, ""
+	}
+	if bodyVersion != nil {
+		return *bodyVersion, false, 0, ""
+	}
+	return 0, false, fiber.StatusPreconditionRequired, "If-Match header or version field is required"
+}
+
+// versionMatch matches the stored version; documents written before versioning have none and count as version 0.
+func versionMatch(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// withVersion adds the optimistic concurrency condition to an update filter.
+func withVersion(filter bson.M, version int64, anyVersion bool) bson.M {
+	if !anyVersion {
+		filter["version"] = versionMatch(version)
+	}
+	return filter
+}
+
+// currentVersion reports the stored version of a document, used to tell a stale write apart from a missing document.
+func currentVersion(ctx context.Context, coll *mongo.Collection, filter bson.M) (int64, error) {
+	var doc struct {
+		Version int64 `bson:"version"`
+	}
+	if err := coll.FindOne(ctx, filter).Decode(&doc); err != nil {
+		return 0, err
+	}
+	return doc.Version, nil
+}
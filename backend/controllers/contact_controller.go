@@ -0,0 +1,383 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxContactLimit = 100
+
+// ContactRequest is the public contact form. Website is a honeypot: it is hidden from
+// people, so only bots fill it in.
+type ContactRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Category string `json:"category"`
+	Budget   string `json:"budget"`
+	Message  string `json:"message"`
+	Website  string `json:"website"`
+}
+
+type ContactStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type ContactNoteRequest struct {
+	Text string `json:"text"`
+}
+
+// ContactAssigneeRequest assigns a lead to an admin by email; an empty email unassigns it.
+type ContactAssigneeRequest struct {
+	AssignedTo string `json:"assignedTo"`
+}
+
+func AddContactHandler(c *fiber.Ctx) error {
+	var req ContactRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("AddContactHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Answer bots like a real submission so they do not retry with another payload
+	if strings.TrimSpace(req.Website) != "" {
+		log.Printf("AddContactHandler: Dropped submission from %s caught by the honeypot", c.IP())
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message": "Contact submitted successfully",
+		})
+	}
+
+	contact := models.Contact{
+		Name:    strings.TrimSpace(req.Name),
+		Email:   strings.ToLower(strings.TrimSpace(req.Email)),
+		Phone:   strings.TrimSpace(req.Phone),
+		Budget:  strings.TrimSpace(req.Budget),
+		Message: models.NormalizeMultiline(req.Message),
+		Status:  models.ContactStatusNew,
+	}
+	if err := contact.Validate(); err != nil {
+		log.Printf("AddContactHandler: Invalid submission: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if req.Category != "" {
+		var category models.Category
+		if _, err := findCategoryBySlug(ctx, req.Category, &category); err != nil {
+			log.Printf("AddContactHandler: Category %s not found: %v", req.Category, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		// A category hidden itself or through an ancestor is unknown to the public form
+		public, err := isCategoryPublic(ctx, &category)
+		if err != nil {
+			log.Printf("AddContactHandler: Failed to check visibility of category %s: %v", req.Category, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to submit contact",
+			})
+		}
+		if !public {
+			log.Printf("AddContactHandler: Category %s is hidden", req.Category)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		contact.CategoryID = &category.ID
+	}
+
+	contact.CreatedAt = time.Now()
+	contact.Version = 1
+	result, err := configs.ContactsColl.InsertOne(ctx, contact)
+	if err != nil {
+		log.Printf("AddContactHandler: Failed to save contact from %s: %v", contact.Email, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to submit contact",
+		})
+	}
+
+	contact.ID = result.InsertedID.(primitive.ObjectID)
+	var categoryID primitive.ObjectID
+	if contact.CategoryID != nil {
+		categoryID = *contact.CategoryID
+	}
+	publishEvent(models.EventContactCreated, "", contact.ID, categoryID, contact)
+
+	log.Printf("AddContactHandler: Contact %s received from %s", contact.ID.Hex(), contact.Email)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Contact submitted successfully",
+	})
+}
+
+func GetContactsHandler(c *fiber.Ctx) error {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		if !models.IsContactStatus(status) {
+			log.Printf("GetContactsHandler: Invalid status %s", status)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid status",
+			})
+		}
+		filter["status"] = status
+	}
+	if assignedTo := c.Query("assignedTo"); assignedTo != "" {
+		if assignedTo == "none" {
+			filter["assignedTo"] = bson.M{"$exists": false}
+		} else {
+			filter["assignedTo"] = strings.ToLower(assignedTo)
+		}
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxContactLimit {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if categorySlug := c.Query("category"); categorySlug != "" {
+		var category models.Category
+		if _, err := findCategoryBySlug(ctx, categorySlug, &category); err != nil {
+			log.Printf("GetContactsHandler: Category %s not found: %v", categorySlug, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		filter["category_id"] = category.ID
+	}
+
+	total, err := configs.ContactsColl.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("GetContactsHandler: Failed to count contacts: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	cursor, err := configs.ContactsColl.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"createdAt": -1}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		log.Printf("GetContactsHandler: Failed to fetch contacts: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+	contacts := []models.Contact{}
+	if err := cursor.All(ctx, &contacts); err != nil {
+		log.Printf("GetContactsHandler: Failed to process contacts: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process contacts",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Contacts fetched successfully",
+		"data":    contacts,
+		"count":   len(contacts),
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+func GetContactHandler(c *fiber.Ctx) error {
+	objID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		log.Printf("GetContactHandler: Invalid contact ID %s: %v", c.Params("id"), err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var contact models.Contact
+	if err := configs.ContactsColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&contact); err != nil {
+		log.Printf("GetContactHandler: Contact %s not found: %v", objID.Hex(), err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Contact not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Contact fetched successfully",
+		"data":    contact,
+	})
+}
+
+func UpdateContactStatusHandler(c *fiber.Ctx) error {
+	var req ContactStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateContactStatusHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if !models.IsContactStatus(req.Status) {
+		log.Printf("UpdateContactStatusHandler: Invalid status %s", req.Status)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Status must be one of New, Replied, Pending or Closed",
+		})
+	}
+	return updateContact(c, "UpdateContactStatusHandler", bson.M{"$set": bson.M{"status": req.Status}})
+}
+
+func AddContactNoteHandler(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("AddContactNoteHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("AddContactNoteHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	var req ContactNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("AddContactNoteHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	text := models.NormalizeMultiline(req.Text)
+	if text == "" || len([]rune(text)) > models.MaxContactNoteLength {
+		log.Printf("AddContactNoteHandler: Invalid note length %d", len([]rune(text)))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Note must be between 1 and 2000 characters",
+		})
+	}
+
+	note := models.ContactNote{
+		ID:        primitive.NewObjectID(),
+		Author:    userEmail,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	return updateContact(c, "AddContactNoteHandler", bson.M{"$push": bson.M{"notes": note}})
+}
+
+// emailCollation compares email addresses case-insensitively.
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
+func AssignContactHandler(c *fiber.Ctx) error {
+	var req ContactAssigneeRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("AssignContactHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	assignee := strings.ToLower(strings.TrimSpace(req.AssignedTo))
+	if assignee == "" {
+		return updateContact(c, "AssignContactHandler", bson.M{"$unset": bson.M{"assignedTo": ""}})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Leads can only be assigned to someone who can sign in to the dashboard. Users keep
+	// the email as registered, so it is matched without regard to case
+	if err := configs.UsersColl.FindOne(ctx, bson.M{"email": assignee}, options.FindOne().SetCollation(emailCollation)).Err(); err != nil {
+		log.Printf("AssignContactHandler: User %s not found: %v", assignee, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	return updateContact(c, "AssignContactHandler", bson.M{"$set": bson.M{"assignedTo": assignee}})
+}
+
+// updateContact applies update to the contact in the URL, stamping updatedAt and the
+// version, and responds with the updated contact.
+func updateContact(c *fiber.Ctx, handler string, update bson.M) error {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("%s: Invalid contact ID %s: %v", handler, id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	log.Printf("%s: User %s requested to update contact %s", handler, userEmail, id)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updatedAt"] = time.Now()
+	update["$inc"] = bson.M{"version": 1}
+
+	var contact models.Contact
+	err = configs.ContactsColl.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&contact)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.Printf("%s: Contact %s not found", handler, id)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		log.Printf("%s: Failed to update contact %s: %v", handler, id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update contact",
+		})
+	}
+
+	log.Printf("%s: Contact %s updated by user %s", handler, id, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Contact updated successfully",
+		"data":    contact,
+	})
+}
@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"backend/models"
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// eventBacklogSize is how many recent events are kept to replay after a reconnect
+	eventBacklogSize = 200
+	// eventClientBuffer is how far a client may fall behind before it is disconnected
+	eventClientBuffer = 32
+	eventHeartbeat    = 20 * time.Second
+	// eventRetryMs tells EventSource how long to wait before reconnecting
+	eventRetryMs = 3000
+	// eventReset asks a client to reload, as events it missed are no longer in the backlog
+	eventReset = "reset"
+)
+
+// eventBroker fans events out to the open /events streams. It lives in the process, which
+// is enough for the single instance we run; a second instance would need a change stream.
+type eventBroker struct {
+	mu      sync.Mutex
+	clients map[chan models.Event]struct{}
+	backlog []models.Event
+}
+
+var eventStreams = &eventBroker{clients: map[chan models.Event]struct{}{}}
+
+// publish adds the event to the backlog and hands it to every client. A client whose
+// buffer is full is disconnected; it reconnects and catches up from the backlog.
+func (b *eventBroker) publish(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.backlog = append(b.backlog, event)
+	if len(b.backlog) > eventBacklogSize {
+		b.backlog = b.backlog[len(b.backlog)-eventBacklogSize:]
+	}
+	for client := range b.clients {
+		select {
+		case client <- event:
+		default:
+			delete(b.clients, client)
+			close(client)
+		}
+	}
+}
+
+// subscribe registers a client and returns the events it missed since lastEventID. missed
+// is set when lastEventID is no longer in the backlog.
+func (b *eventBroker) subscribe(lastEventID string) (client chan models.Event, replay []models.Event, missed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		missed = true
+		for i, event := range b.backlog {
+			if event.ID == lastEventID {
+				replay = append(replay, b.backlog[i+1:]...)
+				missed = false
+				break
+			}
+		}
+	}
+
+	client = make(chan models.Event, eventClientBuffer)
+	b.clients[client] = struct{}{}
+	return client, replay, missed
+}
+
+func (b *eventBroker) unsubscribe(client chan models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.clients[client]; ok {
+		delete(b.clients, client)
+		close(client)
+	}
+}
+
+// closeAll ends every stream, so shutdown does not wait for them.
+func (b *eventBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for client := range b.clients {
+		delete(b.clients, client)
+		close(client)
+	}
+}
+
+// CloseEventStreams disconnects every /events client.
+func CloseEventStreams() {
+	eventStreams.closeAll()
+}
+
+// eventStreamFilter selects the events a client asked for. Events that do not belong to a
+// category, like tag changes, pass the category filter.
+type eventStreamFilter struct {
+	categories map[primitive.ObjectID]bool
+	types      []string
+}
+
+func (f eventStreamFilter) matches(event models.Event) bool {
+	if len(f.types) > 0 && !models.EventMatches(f.types, event.Type) {
+		return false
+	}
+	if len(f.categories) > 0 && event.CategoryID != nil && !f.categories[*event.CategoryID] {
+		return false
+	}
+	return true
+}
+
+// GetEventsHandler streams content changes as server-sent events. Query parameters:
+// category (comma separated slugs) and types (event filters such as project.*). Clients
+// that reconnect with Last-Event-ID get the events they missed, or a reset event when
+// those are gone and they should reload.
+func GetEventsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("GetEventsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("GetEventsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	filter := eventStreamFilter{categories: map[primitive.ObjectID]bool{}}
+	if raw := c.Query("category"); raw != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for _, slug := range strings.Split(raw, ",") {
+			var category models.Category
+			if _, err := findCategoryBySlug(ctx, strings.TrimSpace(slug), &category); err != nil {
+				log.Printf("GetEventsHandler: Category %s not found: %v", slug, err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Category not found",
+				})
+			}
+			filter.categories[category.ID] = true
+		}
+	}
+	if raw := c.Query("types"); raw != "" {
+		for _, eventType := range strings.Split(raw, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if !models.IsEventFilter(eventType) {
+				log.Printf("GetEventsHandler: Unknown event type %s", eventType)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Unknown event type " + eventType,
+				})
+			}
+			filter.types = append(filter.types, eventType)
+		}
+	}
+
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+
+	// The stream ends when the token expires; the client then has to sign in again
+	expiresIn := time.Duration(-1)
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresIn = time.Until(time.Unix(int64(exp), 0))
+	}
+
+	client, replay, missed := eventStreams.subscribe(lastEventID)
+	log.Printf("GetEventsHandler: User %s connected (categories: %d, types: %v, replaying %d events)", userEmail, len(filter.categories), filter.types, len(replay))
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Keeps proxies from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer eventStreams.unsubscribe(client)
+
+		send := func(event models.Event) bool {
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("GetEventsHandler: Failed to encode event %s: %v", event.ID, err)
+				return true
+			}
+			w.WriteString(models.SSEFrame(event.ID, data))
+			return w.Flush() == nil
+		}
+
+		w.WriteString("retry: " + strconv.Itoa(eventRetryMs) + "\n\n")
+		if missed {
+			if !send(models.Event{Type: eventReset, OccurredAt: time.Now()}) {
+				return
+			}
+		}
+		for _, event := range replay {
+			if filter.matches(event) && !send(event) {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		var expired <-chan time.Time
+		if expiresIn >= 0 {
+			timer := time.NewTimer(expiresIn)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		for {
+			select {
+			case event, open := <-client:
+				if !open {
+					return
+				}
+				if filter.matches(event) && !send(event) {
+					log.Printf("GetEventsHandler: User %s disconnected", userEmail)
+					return
+				}
+			case <-heartbeat.C:
+				// Comment lines keep idle connections from being closed by proxies
+				w.WriteString(": heartbeat\n\n")
+				if err := w.Flush(); err != nil {
+					log.Printf("GetEventsHandler: User %s disconnected", userEmail)
+					return
+				}
+			case <-expired:
+				log.Printf("GetEventsHandler: Token of user %s expired, closing stream", userEmail)
+				return
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"backend/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEventBrokerReplay(t *testing.T) {
+	broker := &eventBroker{clients: map[chan models.Event]struct{}{}}
+	for _, id := range []string{"1", "2", "3"} {
+		broker.publish(models.Event{ID: id, Type: models.EventProjectCreated})
+	}
+
+	client, replay, missed := broker.subscribe("1")
+	if missed || len(replay) != 2 || replay[0].ID != "2" || replay[1].ID != "3" {
+		t.Errorf("subscribe(1) replayed %v, missed %t; want events 2 and 3", replay, missed)
+	}
+	broker.publish(models.Event{ID: "4", Type: models.EventProjectUpdated})
+	if event := <-client; event.ID != "4" {
+		t.Errorf("client received %s; want 4", event.ID)
+	}
+	broker.unsubscribe(client)
+	if _, open := <-client; open {
+		t.Errorf("client channel still open after unsubscribe")
+	}
+
+	if _, replay, missed := broker.subscribe("unknown"); !missed || len(replay) != 0 {
+		t.Errorf("subscribe(unknown) replayed %v, missed %t; want a miss", replay, missed)
+	}
+	if _, replay, missed := broker.subscribe(""); missed || len(replay) != 0 {
+		t.Errorf("subscribe() replayed %v, missed %t; want nothing", replay, missed)
+	}
+}
+
+func TestEventBrokerDropsSlowClients(t *testing.T) {
+	broker := &eventBroker{clients: map[chan models.Event]struct{}{}}
+	client, _, _ := broker.subscribe("")
+	for i := 0; i <= eventClientBuffer; i++ {
+		broker.publish(models.Event{ID: primitive.NewObjectID().Hex()})
+	}
+	received := 0
+	for range client {
+		received++
+	}
+	if received != eventClientBuffer {
+		t.Errorf("slow client received %d events before being dropped; want %d", received, eventClientBuffer)
+	}
+}
+
+func TestEventStreamFilter(t *testing.T) {
+	design, print := primitive.NewObjectID(), primitive.NewObjectID()
+	filter := eventStreamFilter{
+		categories: map[primitive.ObjectID]bool{design: true},
+		types:      []string{"project.*"},
+	}
+	cases := []struct {
+		event models.Event
+		want  bool
+	}{
+		{models.Event{Type: models.EventProjectCreated, CategoryID: &design}, true},
+		{models.Event{Type: models.EventProjectCreated, CategoryID: &print}, false},
+		{models.Event{Type: models.EventServiceStepCreated, CategoryID: &design}, false},
+		{models.Event{Type: models.EventProjectDeleted}, true},
+	}
+	for _, tc := range cases {
+		if got := filter.matches(tc.event); got != tc.want {
+			t.Errorf("matches(%s in %v) = %t; want %t", tc.event.Type, tc.event.CategoryID, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/notifications"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publishEvent announces a change that has been written. categoryID is the category the
+// entity belongs to, or the category itself for category events. It does not fail the
+// request: stream clients get it from memory, webhook deliveries are queued and
+// notifications are sent in the background.
+func publishEvent(eventType, actor string, entityID, categoryID primitive.ObjectID, data interface{}) {
+	event := models.Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		Actor:      actor,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+	if !entityID.IsZero() {
+		event.EntityID = &entityID
+	}
+	if !categoryID.IsZero() {
+		event.CategoryID = &categoryID
+	}
+	eventStreams.publish(event)
+	queueWebhookDeliveries(event)
+	notifications.Notify(event)
+}
+
+// revisionEntityEvent returns the updated event for a revisioned entity type.
+func revisionEntityEvent(entityType string) string {
+	switch entityType {
+	case models.RevisionEntityServiceStep:
+		return models.EventServiceStepUpdated
+	case models.RevisionEntitySiteSettings:
+		return models.EventSettingsUpdated
+	}
+	return models.EventProjectUpdated
+}
@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"backend/configs"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+// uploadPolicy limits what can be uploaded as one type of file. Private files are not
+// served by the public /files endpoint.
+type uploadPolicy struct {
+	MaxSize      int64
+	AllowedTypes []string
+	Private      bool
+}
+
+var uploadPolicies = map[string]uploadPolicy{
+	"image": {MaxSize: 10 * 1024 * 1024, AllowedTypes: []string{"image/jpeg", "image/png"}},
+	"video": {MaxSize: 50 * 1024 * 1024, AllowedTypes: []string{"video/mp4", "video/webm"}},
+	// Reference files come from anonymous clients, so they get tighter limits
+	"reference": {MaxSize: 5 * 1024 * 1024, AllowedTypes: []string{"image/jpeg", "image/png", "application/pdf"}, Private: true},
+	"quote":     {MaxSize: 10 * 1024 * 1024, AllowedTypes: []string{"application/pdf"}, Private: true},
+}
+
+// isPrivateFile reports whether an uploaded file may not be served publicly. It fails
+// closed: a file is only public when its metadata can be read and names a public upload type.
+func isPrivateFile(ctx context.Context, fileID primitive.ObjectID) bool {
+	var meta struct {
+		Type string `bson:"type"`
+	}
+	filesColl := configs.Client.Database("ProjectsDB").Collection("FilesColl")
+	if err := filesColl.FindOne(ctx, bson.M{"_id": fileID}).Decode(&meta); err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("isPrivateFile: Failed to fetch metadata of file %s: %v", fileID.Hex(), err)
+		}
+		return true
+	}
+	policy, ok := uploadPolicies[meta.Type]
+	return !ok || policy.Private
+}
+
+// mediaFileID extracts the GridFS file ID from an uploaded file URL
+// (e.g., http://localhost:8081/files/{fileID}). ok is false for external media such as YouTube links.
+func mediaFileID(fileUrl string) (primitive.ObjectID, bool) {
+	parts := strings.Split(fileUrl, "/")
+	if len(parts) < 5 {
+		return primitive.NilObjectID, false
+	}
+	fileID, err := primitive.ObjectIDFromHex(parts[len(parts)-1])
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return fileID, true
+}
+
+// deleteGridFSFile removes an uploaded file and its FilesColl metadata. URLs that do
+// not point at an uploaded file are skipped.
+func deleteGridFSFile(ctx context.Context, bucket *gridfs.Bucket, fileUrl string) error {
+	if fileUrl == "" {
+		return nil
+	}
+	fileID, ok := mediaFileID(fileUrl)
+	if !ok {
+		log.Printf("deleteGridFSFile: Skipping URL without a file ID: %s", fileUrl)
+		return nil
+	}
+	return deleteGridFSFileByID(ctx, bucket, fileID)
+}
+
+// deleteGridFSFileByID removes an uploaded file and its FilesColl metadata.
+func deleteGridFSFileByID(ctx context.Context, bucket *gridfs.Bucket, fileID primitive.ObjectID) error {
+	// Delete file from GridFS (automatically deletes associated chunks)
+	if err := bucket.Delete(fileID); err != nil {
+		log.Printf("deleteGridFSFileByID: Failed to delete file ID %s from GridFS: %v", fileID.Hex(), err)
+		return fmt.Errorf("failed to delete file from GridFS: %v", err)
+	}
+
+	// Delete file metadata from FilesColl
+	_, err := configs.Client.Database("ProjectsDB").Collection("FilesColl").DeleteOne(ctx, bson.M{"_id": fileID})
+	if err != nil {
+		log.Printf("deleteGridFSFileByID: Failed to delete file metadata for file ID %s: %v", fileID.Hex(), err)
+		return fmt.Errorf("failed to delete file metadata: %v", err)
+	}
+
+	log.Printf("deleteGridFSFileByID: File ID %s deleted successfully from GridFS and FilesColl", fileID.Hex())
+	return nil
+}
+
+// copyGridFSFile stores a second copy of an uploaded file and returns its URL, so the
+// copy can be deleted independently of the original. External URLs are returned unchanged.
+func copyGridFSFile(ctx context.Context, bucket *gridfs.Bucket, fileUrl string) (string, error) {
+	fileID, ok := mediaFileID(fileUrl)
+	if !ok {
+		return fileUrl, nil
+	}
+
+	filesColl := configs.Client.Database("ProjectsDB").Collection("FilesColl")
+	var meta struct {
+		Filename string `bson:"filename"`
+		Type     string `bson:"type"`
+		Size     int64  `bson:"size"`
+	}
+	if err := filesColl.FindOne(ctx, bson.M{"_id": fileID}).Decode(&meta); err != nil {
+		log.Printf("copyGridFSFile: Failed to find file metadata for file ID %s: %v", fileID.Hex(), err)
+		return "", fmt.Errorf("failed to find file metadata: %v", err)
+	}
+
+	downloadStream, err := bucket.OpenDownloadStream(fileID)
+	if err != nil {
+		log.Printf("copyGridFSFile: Failed to open download stream for file ID %s: %v", fileID.Hex(), err)
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer downloadStream.Close()
+
+	uploadStream, err := bucket.OpenUploadStream(meta.Filename)
+	if err != nil {
+		log.Printf("copyGridFSFile: Failed to open upload stream for file %s: %v", meta.Filename, err)
+		return "", fmt.Errorf("failed to open upload stream: %v", err)
+	}
+	if _, err := io.Copy(uploadStream, downloadStream); err != nil {
+		uploadStream.Abort()
+		log.Printf("copyGridFSFile: Failed to copy file ID %s: %v", fileID.Hex(), err)
+		return "", fmt.Errorf("failed to copy file: %v", err)
+	}
+	if err := uploadStream.Close(); err != nil {
+		log.Printf("copyGridFSFile: Failed to finish copy of file ID %s: %v", fileID.Hex(), err)
+		return "", fmt.Errorf("failed to copy file: %v", err)
+	}
+
+	copyID := uploadStream.FileID.(primitive.ObjectID)
+	_, err = filesColl.InsertOne(ctx, bson.M{
+		"_id":      copyID,
+		"filename": meta.Filename,
+		"type":     meta.Type,
+		"size":     meta.Size,
+		"uploaded": time.Now(),
+	})
+	if err != nil {
+		log.Printf("copyGridFSFile: Failed to save file metadata for copy of file ID %s: %v", fileID.Hex(), err)
+		bucket.Delete(copyID)
+		return "", fmt.Errorf("failed to save file metadata: %v", err)
+	}
+
+	log.Printf("copyGridFSFile: File ID %s copied to %s", fileID.Hex(), copyID.Hex())
+	return strings.TrimSuffix(fileUrl, fileID.Hex()) + copyID.Hex(), nil
+}
+
+// streamGridFSFile writes an uploaded file to the response with a Content-Type derived
+// from its extension.
+func streamGridFSFile(c *fiber.Ctx, handler string, objID primitive.ObjectID) error {
+	fileID := objID.Hex()
+
+	// Open MongoDB database
+	db := configs.Client.Database("ProjectsDB")
+	bucket, err := gridfs.NewBucket(db)
+	if err != nil {
+		log.Printf("%s: Failed to initialize GridFS: %v", handler, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to initialize GridFS",
+		})
+	}
+
+	// Open download stream
+	downloadStream, err := bucket.OpenDownloadStream(objID)
+	if err != nil {
+		log.Printf("%s: Failed to find file ID %s: %v", handler, fileID, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	}
+	defer downloadStream.Close()
+
+	// Get filename from GridFS file
+	filename := downloadStream.GetFile().Name
+	log.Printf("%s: Serving file %s", handler, filename)
+
+	// Set Content-Type based on file extension
+	var contentType string
+	ext := filepath.Ext(filename)
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg":
+		contentType = "image/jpeg"
+	case ".png":
+		contentType = "image/png"
+	case ".mp4":
+		contentType = "video/mp4"
+	case ".webm":
+		contentType = "video/webm"
+	case ".pdf":
+		contentType = "application/pdf"
+	default:
+		contentType = "application/octet-stream"
+	}
+
+	// Set headers
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+
+	// Stream file to response
+	_, err = io.Copy(c, downloadStream)
+	if err != nil {
+		log.Printf("%s: Failed to stream file %s: %v", handler, filename, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to stream file",
+		})
+	}
+
+	log.Printf("%s: File %s served successfully", handler, filename)
+	return nil
+}
+
+// imageVariantWidths are the widths an uploaded image can be scaled down to through
+// GET /files/:id?w=<width>, keyed by the variant name listed with project media. Only
+// these widths are served so clients cannot make the server resize to arbitrary sizes.
+var imageVariantWidths = map[string]int{
+	"thumbnail": 320,
+	"medium":    640,
+	"large":     1200,
+}
+
+// maxVariantPixels bounds the images that are decoded to build a variant.
+const maxVariantPixels = 40 * 1000 * 1000
+
+// imageVariantURLs returns the URL of every variant of an uploaded image.
+func imageVariantURLs(fileUrl string) map[string]string {
+	variants := make(map[string]string, len(imageVariantWidths))
+	for name, width := range imageVariantWidths {
+		variants[name] = fmt.Sprintf("%s?w=%d", fileUrl, width)
+	}
+	return variants
+}
+
+// isImageVariantWidth reports whether width is one of imageVariantWidths.
+func isImageVariantWidth(width int) bool {
+	for _, w := range imageVariantWidths {
+		if w == width {
+			return true
+		}
+	}
+	return false
+}
+
+// streamImageVariant writes an uploaded JPEG or PNG scaled down to width, in its own
+// format. Images already narrower than width are served as they are.
+func streamImageVariant(c *fiber.Ctx, handler string, objID primitive.ObjectID, width int) error {
+	fileID := objID.Hex()
+
+	bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+	if err != nil {
+		log.Printf("%s: Failed to initialize GridFS: %v", handler, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to initialize GridFS",
+		})
+	}
+	downloadStream, err := bucket.OpenDownloadStream(objID)
+	if err != nil {
+		log.Printf("%s: Failed to find file ID %s: %v", handler, fileID, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	}
+	defer downloadStream.Close()
+
+	filename := downloadStream.GetFile().Name
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jpg", ".jpeg", ".png":
+	default:
+		log.Printf("%s: File %s is not an image, no variant available", handler, filename)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Variants are only available for images",
+		})
+	}
+
+	data, err := io.ReadAll(downloadStream)
+	if err != nil {
+		log.Printf("%s: Failed to read file %s: %v", handler, filename, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to read file",
+		})
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > maxVariantPixels {
+		log.Printf("%s: Cannot build a variant of file %s: %v", handler, filename, err)
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Failed to build image variant",
+		})
+	}
+
+	c.Set("Content-Type", "image/"+format)
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	if config.Width <= width {
+		log.Printf("%s: File %s is %dpx wide, serving it for width %d", handler, filename, config.Width, width)
+		return c.Send(data)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("%s: Failed to decode image %s: %v", handler, filename, err)
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Failed to build image variant",
+		})
+	}
+	var out bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&out, resizeImage(img, width))
+	} else {
+		err = jpeg.Encode(&out, resizeImage(img, width), &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		log.Printf("%s: Failed to encode %dpx variant of %s: %v", handler, width, filename, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build image variant",
+		})
+	}
+
+	log.Printf("%s: Serving %dpx variant of file %s", handler, width, filename)
+	return c.Send(out.Bytes())
+}
+
+// resizeImage scales img down to width, keeping its aspect ratio. Each target pixel is
+// the average of the source pixels it covers, which avoids the aliasing of plain sampling.
+func resizeImage(img image.Image, width int) image.Image {
+	src := img.Bounds()
+	if width <= 0 || width >= src.Dx() {
+		return img
+	}
+	height := src.Dy() * width / src.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(src.Min.X+(x+1)*src.Dx()/width, x0+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResizeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			c := color.RGBA{A: 255}
+			if x%2 == 0 {
+				c.R = 200
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	got := resizeImage(src, 2)
+	if b := got.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+		t.Fatalf("bounds = %v; want 2x1", b)
+	}
+	r, _, _, a := got.At(0, 0).RGBA()
+	if r>>8 != 100 || a>>8 != 255 {
+		t.Errorf("pixel = r %d a %d; want the average r 100 a 255", r>>8, a>>8)
+	}
+
+	if resizeImage(src, 8) != image.Image(src) {
+		t.Errorf("an image narrower than the width was resized")
+	}
+}
+
+func TestImageVariantURLs(t *testing.T) {
+	variants := imageVariantURLs("http://localhost:8081/files/64b7f0c2a1b2c3d4e5f60718")
+	if got := variants["thumbnail"]; got != "http://localhost:8081/files/64b7f0c2a1b2c3d4e5f60718?w=320" {
+		t.Errorf("thumbnail = %q", got)
+	}
+	for name, width := range imageVariantWidths {
+		if variants[name] == "" || !isImageVariantWidth(width) {
+			t.Errorf("variant %s missing", name)
+		}
+	}
+	if isImageVariantWidth(321) {
+		t.Errorf("isImageVariantWidth(321) = true")
+	}
+}
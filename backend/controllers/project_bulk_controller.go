@@ -0,0 +1,514 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxBulkProjectItems caps how many project IDs one bulk request may name across all operations.
+const maxBulkProjectItems = 500
+
+const (
+	bulkOpDelete    = "delete"
+	bulkOpMove      = "move"
+	bulkOpPublish   = "publish"
+	bulkOpUnpublish = "unpublish"
+	bulkOpTag       = "tag"
+)
+
+const (
+	bulkStatusOK         = "ok"
+	bulkStatusError      = "error"
+	bulkStatusRolledBack = "rolled_back"
+	bulkStatusSkipped    = "skipped"
+)
+
+// BulkProjectOperation applies one operation to a list of projects. TargetCategory is
+// read by move, Add and Remove (tag names or slugs) by tag.
+type BulkProjectOperation struct {
+	Op             string   `json:"op"`
+	IDs            []string `json:"ids"`
+	TargetCategory string   `json:"targetCategory,omitempty"`
+	Add            []string `json:"add,omitempty"`
+	Remove         []string `json:"remove,omitempty"`
+}
+
+// BulkProjectsRequest runs operations in order. With Transactional set, either every item
+// succeeds or no change is kept.
+type BulkProjectsRequest struct {
+	Transactional bool                   `json:"transactional"`
+	Operations    []BulkProjectOperation `json:"operations"`
+}
+
+// BulkItemResult reports the outcome for one project of one operation.
+type BulkItemResult struct {
+	Op     string `json:"op"`
+	Index  int    `json:"index"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkItemOutcome is what one applied item leaves behind: whether the project changed, the
+// audit summary of the project as it was read, its revision snapshot when it still exists,
+// and for deletes the media to remove afterwards.
+type bulkItemOutcome struct {
+	changed  bool
+	before   bson.M
+	snapshot bson.M
+	media    []string
+}
+
+// bulkRevision is a change to record as a project revision once the run is final.
+type bulkRevision struct {
+	id       primitive.ObjectID
+	snapshot bson.M
+}
+
+// bulkStep is a validated operation with its IDs parsed and references resolved.
+type bulkStep struct {
+	op     string
+	index  int
+	ids    []primitive.ObjectID
+	target *models.Category
+	add    []string
+	remove []string
+}
+
+func BulkProjectsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("BulkProjectsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("BulkProjectsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	var req BulkProjectsRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("BulkProjectsHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if len(req.Operations) == 0 {
+		log.Printf("BulkProjectsHandler: No operations given")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "At least one operation is required",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	steps, total, err := prepareBulkSteps(ctx, req.Operations)
+	if err != nil {
+		log.Printf("BulkProjectsHandler: Invalid operations: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf("BulkProjectsHandler: User %s requested %d operations over %d projects (transactional: %t)", userEmail, len(steps), total, req.Transactional)
+
+	bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+	if err != nil {
+		log.Printf("BulkProjectsHandler: Failed to initialize GridFS: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to initialize GridFS",
+		})
+	}
+
+	// Categories before any change, so the events of deleted projects still name theirs
+	categoryOf := bulkProjectCategories(ctx, steps)
+	targetOf := map[int]primitive.ObjectID{}
+	for _, step := range steps {
+		if step.target != nil {
+			targetOf[step.index] = step.target.ID
+		}
+	}
+
+	var results []BulkItemResult
+	var revisions []bulkRevision
+	var media []string
+	// outcomes holds the outcome of every applied item by its index in results
+	var outcomes map[int]bulkItemOutcome
+	// run applies every item; it stops at the first failure when stopOnError is set and
+	// marks the remaining items as skipped.
+	run := func(ctx context.Context, stopOnError bool) error {
+		results = make([]BulkItemResult, 0, total)
+		revisions = nil
+		media = nil
+		outcomes = map[int]bulkItemOutcome{}
+		var failure error
+		for _, step := range steps {
+			for _, id := range step.ids {
+				result := BulkItemResult{Op: step.op, Index: step.index, ID: id.Hex(), Status: bulkStatusOK}
+				if failure != nil {
+					result.Status = bulkStatusSkipped
+					results = append(results, result)
+					continue
+				}
+				outcome, err := applyBulkItem(ctx, step, id)
+				if err != nil {
+					log.Printf("BulkProjectsHandler: Operation %d (%s) failed for project %s: %v", step.index, step.op, id.Hex(), err)
+					result.Status = bulkStatusError
+					result.Error = bulkItemMessage(step.op, err)
+					if stopOnError {
+						failure = err
+					}
+				} else {
+					outcomes[len(results)] = outcome
+					if outcome.changed && outcome.snapshot != nil {
+						revisions = append(revisions, bulkRevision{id: id, snapshot: outcome.snapshot})
+					}
+					media = append(media, outcome.media...)
+				}
+				results = append(results, result)
+			}
+		}
+		return failure
+	}
+
+	status := fiber.StatusOK
+	if req.Transactional {
+		session, err := configs.Client.StartSession()
+		if err != nil {
+			log.Printf("BulkProjectsHandler: Failed to start session: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to start transaction",
+			})
+		}
+		defer session.EndSession(ctx)
+
+		_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+			return nil, run(sessionContext, true)
+		})
+		if err != nil {
+			log.Printf("BulkProjectsHandler: Transaction rolled back: %v", err)
+			for i := range results {
+				if results[i].Status == bulkStatusOK {
+					results[i].Status = bulkStatusRolledBack
+				}
+			}
+			revisions = nil
+			media = nil
+			status = fiber.StatusConflict
+		}
+	} else {
+		run(ctx, false)
+	}
+
+	// Media is removed only once the project documents are gone for good
+	for _, fileUrl := range media {
+		if err := deleteGridFSFile(ctx, bucket, fileUrl); err != nil {
+			log.Printf("BulkProjectsHandler: Failed to delete file %s: %v", fileUrl, err)
+		}
+	}
+
+	for _, revision := range revisions {
+		if _, err := recordRevision(ctx, models.RevisionEntityProject, revision.id, revision.snapshot, userEmail, nil); err != nil {
+			log.Printf("BulkProjectsHandler: Failed to record revision for project %s: %v", revision.id.Hex(), err)
+		}
+	}
+
+	succeeded, failed := 0, 0
+	for i, result := range results {
+		switch result.Status {
+		case bulkStatusOK:
+			succeeded++
+			id, _ := primitive.ObjectIDFromHex(result.ID)
+			outcome := outcomes[i]
+			var after bson.M
+			if outcome.snapshot != nil {
+				after = auditSummaryOf(outcome.snapshot)
+			}
+			recordAudit(c, userEmail, bulkAuditAction(result.Op), models.AuditEntityProject, id, outcome.before, after, fmt.Sprintf("Bulk %s", result.Op))
+			eventType, categoryID := models.EventProjectUpdated, categoryOf[id]
+			switch result.Op {
+			case bulkOpDelete:
+				eventType = models.EventProjectDeleted
+			case bulkOpMove:
+				categoryID = targetOf[result.Index]
+			}
+			publishEvent(eventType, userEmail, id, categoryID, fiber.Map{"_id": id, "op": result.Op})
+		case bulkStatusError:
+			failed++
+		}
+	}
+
+	if status != fiber.StatusOK {
+		return c.Status(status).JSON(fiber.Map{
+			"error":   "Bulk operation failed, no changes were applied",
+			"data":    results,
+			"count":   len(results),
+			"failed":  failed,
+			"applied": 0,
+		})
+	}
+
+	log.Printf("BulkProjectsHandler: %d items succeeded, %d failed for user %s", succeeded, failed, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Bulk operation completed",
+		"data":    results,
+		"count":   len(results),
+		"failed":  failed,
+		"applied": succeeded,
+	})
+}
+
+// bulkAuditAction maps a bulk operation to the audit action it is logged as.
+func bulkAuditAction(op string) string {
+	switch op {
+	case bulkOpDelete:
+		return models.AuditActionDelete
+	case bulkOpMove:
+		return models.AuditActionMove
+	}
+	return models.AuditActionUpdate
+}
+
+// bulkProjectCategories maps the projects named in the steps to their current category.
+func bulkProjectCategories(ctx context.Context, steps []bulkStep) map[primitive.ObjectID]primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, step := range steps {
+		ids = append(ids, step.ids...)
+	}
+	categoryOf := map[primitive.ObjectID]primitive.ObjectID{}
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"_id": bson.M{"$in": uniqueObjectIDs(ids)}},
+		options.Find().SetProjection(bson.M{"category_id": 1}))
+	if err != nil {
+		log.Printf("bulkProjectCategories: Failed to fetch project categories: %v", err)
+		return categoryOf
+	}
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("bulkProjectCategories: Failed to process project categories: %v", err)
+		return categoryOf
+	}
+	for _, project := range projects {
+		categoryOf[project.ID] = project.CategoryID
+	}
+	return categoryOf
+}
+
+// prepareBulkSteps validates the operations up front so a typo in one of them does not
+// leave the others half applied. It returns the steps and the total number of items.
+func prepareBulkSteps(ctx context.Context, operations []BulkProjectOperation) ([]bulkStep, int, error) {
+	steps := make([]bulkStep, 0, len(operations))
+	total := 0
+	targets := map[string]*models.Category{}
+	for index, operation := range operations {
+		step := bulkStep{op: strings.ToLower(strings.TrimSpace(operation.Op)), index: index}
+		switch step.op {
+		case bulkOpDelete, bulkOpPublish, bulkOpUnpublish:
+		case bulkOpMove:
+			if operation.TargetCategory == "" {
+				return nil, 0, fmt.Errorf("Operation %d: target category is required", index)
+			}
+			target, ok := targets[operation.TargetCategory]
+			if !ok {
+				target = &models.Category{}
+				if _, err := findCategoryBySlug(ctx, operation.TargetCategory, target); err != nil {
+					return nil, 0, fmt.Errorf("Operation %d: target category not found", index)
+				}
+				targets[operation.TargetCategory] = target
+			}
+			step.target = target
+		case bulkOpTag:
+			var err error
+			if step.add, err = resolveTagSlugs(ctx, operation.Add); err != nil {
+				return nil, 0, fmt.Errorf("Operation %d: %v", index, err)
+			}
+			// Removing a tag that no longer exists is allowed, so only normalize
+			step.remove = models.ParseTagList(strings.Join(operation.Remove, ","))
+			if len(step.add) == 0 && len(step.remove) == 0 {
+				return nil, 0, fmt.Errorf("Operation %d: tags to add or remove are required", index)
+			}
+		default:
+			return nil, 0, fmt.Errorf("Operation %d: unknown operation %q", index, operation.Op)
+		}
+
+		if len(operation.IDs) == 0 {
+			return nil, 0, fmt.Errorf("Operation %d: project IDs are required", index)
+		}
+		seen := map[primitive.ObjectID]bool{}
+		for _, raw := range operation.IDs {
+			objID, err := primitive.ObjectIDFromHex(raw)
+			if err != nil {
+				return nil, 0, fmt.Errorf("Operation %d: invalid project ID %s", index, raw)
+			}
+			if !seen[objID] {
+				seen[objID] = true
+				step.ids = append(step.ids, objID)
+			}
+		}
+		total += len(step.ids)
+		if total > maxBulkProjectItems {
+			return nil, 0, fmt.Errorf("At most %d projects can be changed in one request", maxBulkProjectItems)
+		}
+		steps = append(steps, step)
+	}
+	return steps, total, nil
+}
+
+// Outcomes of a bulk item that are reported as they are; any other error is a driver error.
+var (
+	errBulkProjectNotFound = fmt.Errorf("Project not found")
+	errBulkProjectChanged  = fmt.Errorf("Project was changed by someone else")
+)
+
+// bulkItemMessage turns the error of a bulk item into the message of its result. Driver
+// errors are only logged: they stay wrapped so a transaction can still see their labels.
+func bulkItemMessage(op string, err error) string {
+	switch {
+	case errors.Is(err, errBulkProjectNotFound), errors.Is(err, errBulkProjectChanged):
+		return err.Error()
+	case errors.Is(err, errProjectsChanged):
+		return errBulkProjectChanged.Error()
+	}
+	switch op {
+	case bulkOpDelete:
+		return "Failed to delete project"
+	case bulkOpMove:
+		return "Failed to move project"
+	}
+	return "Failed to update project"
+}
+
+// applyBulkItem runs one operation on one project and reports its outcome.
+func applyBulkItem(ctx context.Context, step bulkStep, id primitive.ObjectID) (bulkItemOutcome, error) {
+	var project models.Project
+	if err := configs.ProjectsColl.FindOne(ctx, bson.M{"_id": id}).Decode(&project); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return bulkItemOutcome{}, errBulkProjectNotFound
+		}
+		return bulkItemOutcome{}, fmt.Errorf("fetch project %s: %w", id.Hex(), err)
+	}
+
+	before := auditSummaryOf(project)
+	now := time.Now()
+	switch step.op {
+	case bulkOpDelete:
+		// Same order as DeleteProjectHandler: service steps first, then the project itself
+		if _, err := configs.ServiceStepsColl.DeleteMany(ctx, bson.M{"project_id": id}); err != nil {
+			return bulkItemOutcome{}, fmt.Errorf("delete service steps of project %s: %w", id.Hex(), err)
+		}
+		result, err := configs.ProjectsColl.DeleteOne(ctx, bson.M{"_id": id})
+		if err != nil {
+			return bulkItemOutcome{}, fmt.Errorf("delete project %s: %w", id.Hex(), err)
+		}
+		if result.DeletedCount == 0 {
+			return bulkItemOutcome{}, errBulkProjectNotFound
+		}
+		return bulkItemOutcome{changed: true, before: before, media: []string{project.ImageUrl, project.VideoUrl}}, nil
+
+	case bulkOpMove:
+		if project.CategoryID == step.target.ID {
+			return bulkItemOutcome{}, nil
+		}
+		position, err := nextSequence(ctx, configs.ProjectsColl, step.target.ID, "position", 0)
+		if err != nil {
+			return bulkItemOutcome{}, fmt.Errorf("read positions of category %s: %w", step.target.Slug, err)
+		}
+		snapshot, err := relocateProject(ctx, &project, step.target.ID, position, now)
+		if err != nil {
+			return bulkItemOutcome{}, fmt.Errorf("move project %s: %w", id.Hex(), err)
+		}
+		return bulkItemOutcome{changed: true, before: before, snapshot: snapshot}, nil
+
+	case bulkOpPublish, bulkOpUnpublish:
+		published := step.op == bulkOpPublish
+		if project.Published == published {
+			return bulkItemOutcome{}, nil
+		}
+		return updateBulkProject(ctx, &project, before, bson.M{"published": published, "updatedAt": now})
+
+	case bulkOpTag:
+		tags := bulkTagList(project.Tags, step.add, step.remove)
+		if strings.Join(tags, ",") == strings.Join(project.Tags, ",") {
+			return bulkItemOutcome{}, nil
+		}
+		return updateBulkProject(ctx, &project, before, bson.M{"tags": tags, "updatedAt": now})
+	}
+	return bulkItemOutcome{}, fmt.Errorf("unknown operation %q", step.op)
+}
+
+// updateBulkProject applies set to the project as long as its version is still the one read.
+func updateBulkProject(ctx context.Context, project *models.Project, before, set bson.M) (bulkItemOutcome, error) {
+	if err := ensureBaselineRevision(ctx, configs.ProjectsColl, models.RevisionEntityProject, project.ID); err != nil {
+		return bulkItemOutcome{}, fmt.Errorf("record baseline of project %s: %w", project.ID.Hex(), err)
+	}
+	snapshot, err := decodeWithSnapshot(configs.ProjectsColl.FindOneAndUpdate(ctx,
+		bson.M{"_id": project.ID, "version": project.Version},
+		bson.M{
+			"$set": set,
+			"$inc": bson.M{"version": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	), nil)
+	if err == mongo.ErrNoDocuments {
+		return bulkItemOutcome{}, errBulkProjectChanged
+	}
+	if err != nil {
+		return bulkItemOutcome{}, fmt.Errorf("update project %s: %w", project.ID.Hex(), err)
+	}
+	return bulkItemOutcome{changed: true, before: before, snapshot: snapshot}, nil
+}
+
+// bulkTagList returns current without remove, followed by the tags in add it lacks.
+func bulkTagList(current, add, remove []string) []string {
+	drop := map[string]bool{}
+	for _, slug := range remove {
+		drop[slug] = true
+	}
+	tags := []string{}
+	has := map[string]bool{}
+	for _, slug := range current {
+		if !drop[slug] && !has[slug] {
+			has[slug] = true
+			tags = append(tags, slug)
+		}
+	}
+	for _, slug := range add {
+		if !has[slug] {
+			has[slug] = true
+			tags = append(tags, slug)
+		}
+	}
+	return tags
+}
+
+func uniqueObjectIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
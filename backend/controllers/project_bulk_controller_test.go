@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBulkTagList(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		add     []string
+		remove  []string
+		want    []string
+	}{
+		{"add to empty", nil, []string{"logo"}, nil, []string{"logo"}},
+		{"keeps order and appends", []string{"logo", "print"}, []string{"web"}, nil, []string{"logo", "print", "web"}},
+		{"removes", []string{"logo", "print"}, nil, []string{"logo"}, []string{"print"}},
+		{"remove missing tag", []string{"logo"}, nil, []string{"web"}, []string{"logo"}},
+		{"add existing tag", []string{"logo"}, []string{"logo"}, nil, []string{"logo"}},
+		{"drops duplicates", []string{"logo", "logo"}, []string{"web", "web"}, nil, []string{"logo", "web"}},
+		{"remove then add back", []string{"logo"}, []string{"logo"}, []string{"logo"}, []string{"logo"}},
+		{"remove all", []string{"logo"}, nil, []string{"logo"}, []string{}},
+	}
+	for _, test := range tests {
+		if got := bulkTagList(test.current, test.add, test.remove); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: bulkTagList = %v; want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPrepareBulkStepsValidation(t *testing.T) {
+	id := "65f1c0a2b3d4e5f6a7b8c9d0"
+	tooMany := make([]string, maxBulkProjectItems+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("65f1c0a2b3d4e5f6a7%06x", i)
+	}
+
+	tests := []struct {
+		name       string
+		operations []BulkProjectOperation
+		want       string
+	}{
+		{"unknown operation", []BulkProjectOperation{{Op: "archive", IDs: []string{id}}}, `Operation 0: unknown operation "archive"`},
+		{"move without target", []BulkProjectOperation{{Op: "move", IDs: []string{id}}}, "Operation 0: target category is required"},
+		{"tag without tags", []BulkProjectOperation{{Op: "tag", IDs: []string{id}, Remove: []string{" "}}}, "Operation 0: tags to add or remove are required"},
+		{"no IDs", []BulkProjectOperation{{Op: "publish"}, {Op: "delete"}}, "Operation 0: project IDs are required"},
+		{"invalid ID", []BulkProjectOperation{{Op: "publish", IDs: []string{id}}, {Op: "Unpublish", IDs: []string{"nope"}}}, "Operation 1: invalid project ID nope"},
+		{"too many projects", []BulkProjectOperation{{Op: "delete", IDs: tooMany}}, "At most 500 projects"},
+	}
+	for _, test := range tests {
+		_, _, err := prepareBulkSteps(context.Background(), test.operations)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: prepareBulkSteps error = %v; want one containing %q", test.name, err, test.want)
+		}
+	}
+
+	steps, total, err := prepareBulkSteps(context.Background(), []BulkProjectOperation{
+		{Op: " Publish ", IDs: []string{id, id}},
+		{Op: "tag", IDs: []string{id}, Remove: []string{"Logo"}},
+	})
+	if err != nil {
+		t.Fatalf("prepareBulkSteps: %v", err)
+	}
+	if total != 2 || len(steps) != 2 || steps[0].op != bulkOpPublish || len(steps[0].ids) != 1 {
+		t.Errorf("steps = %+v, total %d; want publish of one project and a tag step", steps, total)
+	}
+	if !reflect.DeepEqual(steps[1].remove, []string{"logo"}) {
+		t.Errorf("tag step removes %v; want [logo]", steps[1].remove)
+	}
+}
+
+func TestBulkItemMessageKeepsDriverError(t *testing.T) {
+	driverErr := mongo.CommandError{Code: 112, Name: "WriteConflict", Labels: []string{"TransientTransactionError"}}
+	err := fmt.Errorf("update project %s: %w", "65f1c0a2b3d4e5f6a7b8c9d0", driverErr)
+
+	var labeled mongo.LabeledError
+	if !errors.As(err, &labeled) || !labeled.HasErrorLabel("TransientTransactionError") {
+		t.Errorf("wrapped error %v lost the TransientTransactionError label", err)
+	}
+	if got := bulkItemMessage(bulkOpTag, err); got != "Failed to update project" {
+		t.Errorf("bulkItemMessage(tag, driver error) = %q", got)
+	}
+	if got := bulkItemMessage(bulkOpMove, fmt.Errorf("move project: %w", errProjectsChanged)); got != errBulkProjectChanged.Error() {
+		t.Errorf("bulkItemMessage(move, changed) = %q", got)
+	}
+	if got := bulkItemMessage(bulkOpDelete, errBulkProjectNotFound); got != "Project not found" {
+		t.Errorf("bulkItemMessage(delete, not found) = %q", got)
+	}
+}
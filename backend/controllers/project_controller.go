@@ -8,7 +8,6 @@ import (
 	"io"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -18,13 +17,20 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ProjectRequest struct {
-	ImageUrl string `json:"imageUrl,omitempty"`
-	VideoUrl string `json:"videoUrl,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Slug        *string   `json:"slug,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Published   *bool     `json:"published,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	ImageUrl    *string   `json:"imageUrl,omitempty"`
+	VideoUrl    *string   `json:"videoUrl,omitempty"`
+	Version     *int64    `json:"version,omitempty"`
 }
 
 type File struct {
@@ -38,21 +44,19 @@ type File struct {
 func uploadToGridFS(ctx context.Context, file *multipart.FileHeader, fileType string) (string, error) {
 	log.Printf("uploadToGridFS: Uploading file %s of type %s", file.Filename, fileType)
 
-	// Validate file size
-	maxSize := int64(10 * 1024 * 1024) // 10MB for image
-	if fileType == "video" {
-		maxSize = 50 * 1024 * 1024 // 50MB for video
+	// Validate file size and type against the limits for this kind of upload
+	policy, ok := uploadPolicies[fileType]
+	if !ok {
+		log.Printf("uploadToGridFS: Unknown upload type %s for file %s", fileType, file.Filename)
+		return "", fmt.Errorf("invalid upload type %s", fileType)
 	}
+	maxSize := policy.MaxSize
 	if file.Size > maxSize {
 		log.Printf("uploadToGridFS: File %s too large, size: %d bytes, max: %d bytes", file.Filename, file.Size, maxSize)
 		return "", fmt.Errorf("file too large, max size is %dMB", maxSize/(1024*1024))
 	}
 
-	// Validate file type
-	allowedTypes := []string{"image/jpeg", "image/png"}
-	if fileType == "video" {
-		allowedTypes = []string{"video/mp4", "video/webm"}
-	}
+	allowedTypes := policy.AllowedTypes
 	contentType := file.Header.Get("Content-Type")
 	isValidType := false
 	for _, t := range allowedTypes {
@@ -170,6 +174,16 @@ func UploadFileHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	fileID, _ := mediaFileID(fileUrl)
+	uploaded := bson.M{
+		"filename": file.Filename,
+		"type":     fileType,
+		"size":     file.Size,
+	}
+	recordAudit(c, userEmail, models.AuditActionUpload, models.AuditEntityFile, fileID, nil, uploaded, "")
+	uploaded["url"] = fileUrl
+	publishEvent(models.EventFileUploaded, userEmail, fileID, primitive.NilObjectID, uploaded)
+
 	log.Printf("UploadFileHandler: File %s uploaded successfully by user %s", file.Filename, userEmail)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "File uploaded successfully",
@@ -194,24 +208,24 @@ func AddProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("AddProjectHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("AddProjectHandler: User %s requested to add project in category %s", userEmail, categoryName)
+	log.Printf("AddProjectHandler: User %s requested to add project in category %s", userEmail, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Find category_id
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("AddProjectHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("AddProjectHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
@@ -241,6 +255,53 @@ func AddProjectHandler(c *fiber.Ctx) error {
 		CategoryID: category.ID,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
+		Version:    1,
+		Published:  true,
+	}
+	if len(form.Value["title"]) > 0 {
+		project.Title = models.NormalizeText(form.Value["title"][0])
+	}
+	if len(form.Value["description"]) > 0 {
+		project.Description = strings.TrimSpace(form.Value["description"][0])
+	}
+	if len(form.Value["published"]) > 0 {
+		project.Published = form.Value["published"][0] != "false"
+	}
+	if len(form.Value["tags"]) > 0 {
+		project.Tags, err = resolveTagSlugs(ctx, form.Value["tags"])
+		if err != nil {
+			log.Printf("AddProjectHandler: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	// An explicit slug must be free in the category; otherwise one is derived from the title
+	if len(form.Value["slug"]) > 0 && form.Value["slug"][0] != "" {
+		slug := form.Value["slug"][0]
+		if !models.IsSlugValid(slug) {
+			log.Printf("AddProjectHandler: Invalid slug %s", slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Slug must be lowercase letters, digits and single dashes",
+			})
+		}
+		count, err := configs.ProjectsColl.CountDocuments(ctx, bson.M{"category_id": category.ID, "slug": slug})
+		if err != nil || count > 0 {
+			log.Printf("AddProjectHandler: Slug %s already used in category %s: %v", slug, categorySlug, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Project slug already exists in this category",
+			})
+		}
+		project.Slug = slug
+	} else {
+		project.Slug, err = uniqueProjectSlug(ctx, category.ID, project.Title, primitive.NilObjectID)
+		if err != nil {
+			log.Printf("AddProjectHandler: Failed to derive slug for project: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to create project",
+			})
+		}
 	}
 
 	// Handle file upload
@@ -270,17 +331,43 @@ func AddProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Insert project into database
-	result, err := configs.ProjectsColl.InsertOne(ctx, project)
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("AddProjectHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create project",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	// New projects go to the top of the manual order; the shift and the insert are one
+	// transaction so a failed insert leaves the order as it was
+	insertedID, err := session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		if _, err := configs.ProjectsColl.UpdateMany(sessionContext, bson.M{"category_id": category.ID}, bson.M{"$inc": bson.M{"position": 1}}); err != nil {
+			return nil, fmt.Errorf("shift project positions: %w", err)
+		}
+		result, err := configs.ProjectsColl.InsertOne(sessionContext, project)
+		if err != nil {
+			return nil, fmt.Errorf("insert project: %w", err)
+		}
+		return result.InsertedID, nil
+	})
 	if err != nil {
-		log.Printf("AddProjectHandler: Failed to insert project: %v", err)
+		log.Printf("AddProjectHandler: Transaction failed creating project in category %s: %v", categorySlug, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create project",
 		})
 	}
 
-	project.ID = result.InsertedID.(primitive.ObjectID)
-	log.Printf("AddProjectHandler: Project %s created successfully by user %s in category %s", project.ID.Hex(), userEmail, categoryName)
+	project.ID = insertedID.(primitive.ObjectID)
+	c.Set(fiber.HeaderETag, formatETag(project.Version))
+	if err := recordCreatedRevision(ctx, models.RevisionEntityProject, project.ID, project, userEmail); err != nil {
+		log.Printf("AddProjectHandler: Failed to record revision for project %s: %v", project.ID.Hex(), err)
+	}
+	recordAudit(c, userEmail, models.AuditActionCreate, models.AuditEntityProject, project.ID, nil, auditSummaryOf(project), "")
+	publishEvent(models.EventProjectCreated, userEmail, project.ID, project.CategoryID, project)
+
+	log.Printf("AddProjectHandler: Project %s created successfully by user %s in category %s", project.ID.Hex(), userEmail, categorySlug)
 	return c.JSON(fiber.Map{
 		"message": "Project created successfully",
 		"data":    project,
@@ -307,61 +394,28 @@ func GetFileHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Open MongoDB database
-	db := configs.Client.Database("ProjectsDB")
-	bucket, err := gridfs.NewBucket(db)
-	if err != nil {
-		log.Printf("GetFileHandler: Failed to initialize GridFS: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to initialize GridFS",
-		})
-	}
-
-	// Open download stream
-	downloadStream, err := bucket.OpenDownloadStream(objID)
-	if err != nil {
-		log.Printf("GetFileHandler: Failed to find file ID %s: %v", fileID, err)
+	// Quote attachments and documents are only served through the authenticated quote endpoints
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if isPrivateFile(ctx, objID) {
+		log.Printf("GetFileHandler: File ID %s is private", fileID)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "File not found",
 		})
 	}
-	defer downloadStream.Close()
-
-	// Get filename from GridFS file
-	filename := downloadStream.GetFile().Name
-	log.Printf("GetFileHandler: Serving file %s", filename)
-
-	// Set Content-Type based on file extension
-	var contentType string
-	ext := filepath.Ext(filename)
-	switch strings.ToLower(ext) {
-	case ".jpg", ".jpeg":
-		contentType = "image/jpeg"
-	case ".png":
-		contentType = "image/png"
-	case ".mp4":
-		contentType = "video/mp4"
-	case ".webm":
-		contentType = "video/webm"
-	default:
-		contentType = "application/octet-stream"
-	}
-
-	// Set headers
-	c.Set("Content-Type", contentType)
-	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
 
-	// Stream file to response
-	_, err = io.Copy(c, downloadStream)
-	if err != nil {
-		log.Printf("GetFileHandler: Failed to stream file %s: %v", filename, err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to stream file",
-		})
+	// ?w= asks for one of the scaled down image variants listed with project media
+	if width := c.QueryInt("w"); width != 0 {
+		if !isImageVariantWidth(width) {
+			log.Printf("GetFileHandler: Unsupported variant width %d for file ID %s", width, fileID)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Unsupported image width",
+			})
+		}
+		return streamImageVariant(c, "GetFileHandler", objID, width)
 	}
 
-	log.Printf("GetFileHandler: File %s served successfully", filename)
-	return nil
+	return streamGridFSFile(c, "GetFileHandler", objID)
 }
 
 func UpdateProjectHandler(c *fiber.Ctx) error {
@@ -389,23 +443,23 @@ func UpdateProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("UpdateProjectHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("UpdateProjectHandler: User %s requested to update project ID %s in category %s", userEmail, id, categoryName)
+	log.Printf("UpdateProjectHandler: User %s requested to update project ID %s in category %s", userEmail, id, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("UpdateProjectHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("UpdateProjectHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
@@ -427,40 +481,106 @@ func UpdateProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateProjectHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	set := bson.M{
+		"category_id": category.ID,
+		"updatedAt":   time.Now(),
+	}
+	// Fields are only touched when sent, so a partial update keeps the rest of the project
+	if req.ImageUrl != nil {
+		set["imageUrl"] = *req.ImageUrl
+	}
+	if req.VideoUrl != nil {
+		set["videoUrl"] = *req.VideoUrl
+	}
+	if req.Title != nil {
+		set["title"] = models.NormalizeText(*req.Title)
+	}
+	if req.Description != nil {
+		set["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Published != nil {
+		set["published"] = *req.Published
+	}
+	if req.Tags != nil {
+		tags, err := resolveTagSlugs(ctx, *req.Tags)
+		if err != nil {
+			log.Printf("UpdateProjectHandler: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		set["tags"] = tags
+	}
+	// The slug only changes when explicitly requested so shared links keep working
+	if req.Slug != nil && *req.Slug != "" {
+		if !models.IsSlugValid(*req.Slug) {
+			log.Printf("UpdateProjectHandler: Invalid slug %s", *req.Slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Slug must be lowercase letters, digits and single dashes",
+			})
+		}
+		count, err := configs.ProjectsColl.CountDocuments(ctx, bson.M{"category_id": category.ID, "slug": *req.Slug, "_id": bson.M{"$ne": objID}})
+		if err != nil || count > 0 {
+			log.Printf("UpdateProjectHandler: Slug %s already used in category %s: %v", *req.Slug, categorySlug, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Project slug already exists in this category",
+			})
+		}
+		set["slug"] = *req.Slug
+	}
+
 	update := bson.M{
-		"$set": bson.M{
-			"imageUrl":    req.ImageUrl,
-			"videoUrl":    req.VideoUrl,
-			"category_id": category.ID,
-			"updatedAt":   time.Now(),
-		},
+		"$set": set,
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := configs.ProjectsColl.UpdateOne(ctx, bson.M{"_id": objID, "category_id": category.ID}, update)
-	if err != nil {
+	filter := bson.M{"_id": objID, "category_id": category.ID}
+	if err := ensureBaselineRevision(ctx, configs.ProjectsColl, models.RevisionEntityProject, objID); err != nil {
+		log.Printf("UpdateProjectHandler: Failed to record baseline revision for project %s: %v", id, err)
+	}
+	before := auditSnapshot(ctx, configs.ProjectsColl, objID)
+	var updatedProject models.Project
+	snapshot, err := decodeWithSnapshot(configs.ProjectsColl.FindOneAndUpdate(ctx, withVersion(filter, version, anyVersion), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)), &updatedProject)
+	if err != nil && err != mongo.ErrNoDocuments {
 		log.Printf("UpdateProjectHandler: Failed to update project ID %s: %v", id, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update project",
 		})
 	}
 
-	if result.MatchedCount == 0 {
-		log.Printf("UpdateProjectHandler: No project matched for ID %s in category %s", id, categoryName)
+	if err == mongo.ErrNoDocuments {
+		current, err := currentVersion(ctx, configs.ProjectsColl, bson.M{"_id": objID, "category_id": category.ID})
+		if err == nil {
+			log.Printf("UpdateProjectHandler: Stale write for project ID %s, expected version %d, current %d", id, version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Project was modified by someone else",
+				"version": current,
+			})
+		}
+		log.Printf("UpdateProjectHandler: No project matched for ID %s in category %s", id, categorySlug)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Project not found or category does not match",
 		})
 	}
 
-	// Fetch the updated project
-	var updatedProject models.Project
-	err = configs.ProjectsColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&updatedProject)
-	if err != nil {
-		log.Printf("UpdateProjectHandler: Failed to fetch updated project ID %s: %v", id, err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch updated project",
-		})
+	if _, err := recordRevision(ctx, models.RevisionEntityProject, objID, snapshot, userEmail, nil); err != nil {
+		log.Printf("UpdateProjectHandler: Failed to record revision for project %s: %v", id, err)
 	}
 
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityProject, objID, before, auditSummaryOf(updatedProject), "")
+	publishEvent(models.EventProjectUpdated, userEmail, objID, updatedProject.CategoryID, updatedProject)
+
+	c.Set(fiber.HeaderETag, formatETag(updatedProject.Version))
 	log.Printf("UpdateProjectHandler: Project %s updated successfully by user %s", id, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Project updated successfully",
@@ -493,23 +613,23 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("DeleteProjectHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("DeleteProjectHandler: User %s requested to delete project ID %s in category %s", userEmail, id, categoryName)
+	log.Printf("DeleteProjectHandler: User %s requested to delete project ID %s in category %s", userEmail, id, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("DeleteProjectHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("DeleteProjectHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
@@ -527,7 +647,7 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 	var project models.Project
 	err = configs.ProjectsColl.FindOne(ctx, bson.M{"_id": objID, "category_id": category.ID}).Decode(&project)
 	if err != nil {
-		log.Printf("DeleteProjectHandler: Project ID %s not found in category %s: %v", id, categoryName, err)
+		log.Printf("DeleteProjectHandler: Project ID %s not found in category %s: %v", id, categorySlug, err)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Project not found or category does not match",
 		})
@@ -543,49 +663,13 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Function to delete a file from GridFS if it exists
-	deleteGridFSFile := func(fileUrl string) error {
-		if fileUrl == "" {
-			return nil
-		}
-		// Extract file ID from URL (e.g., http://localhost:8081/files/{fileID})
-		parts := strings.Split(fileUrl, "/")
-		if len(parts) < 5 {
-			log.Printf("DeleteProjectHandler: Invalid file URL format: %s", fileUrl)
-			return nil // Skip invalid URLs
-		}
-		fileIDStr := parts[len(parts)-1]
-		fileID, err := primitive.ObjectIDFromHex(fileIDStr)
-		if err != nil {
-			log.Printf("DeleteProjectHandler: Invalid file ID in URL %s: %v", fileUrl, err)
-			return nil // Skip invalid file IDs
-		}
-
-		// Delete file from GridFS (automatically deletes associated chunks)
-		err = bucket.Delete(fileID)
-		if err != nil {
-			log.Printf("DeleteProjectHandler: Failed to delete file ID %s from GridFS: %v", fileID.Hex(), err)
-			return fmt.Errorf("failed to delete file from GridFS: %v", err)
-		}
-
-		// Delete file metadata from FilesColl
-		_, err = configs.Client.Database("ProjectsDB").Collection("FilesColl").DeleteOne(ctx, bson.M{"_id": fileID})
-		if err != nil {
-			log.Printf("DeleteProjectHandler: Failed to delete file metadata for file ID %s: %v", fileID.Hex(), err)
-			return fmt.Errorf("failed to delete file metadata: %v", err)
-		}
-
-		log.Printf("DeleteProjectHandler: File ID %s deleted successfully from GridFS and FilesColl", fileID.Hex())
-		return nil
-	}
-
 	// Delete associated files from GridFS
-	if err := deleteGridFSFile(project.ImageUrl); err != nil {
+	if err := deleteGridFSFile(ctx, bucket, project.ImageUrl); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
-	if err := deleteGridFSFile(project.VideoUrl); err != nil {
+	if err := deleteGridFSFile(ctx, bucket, project.VideoUrl); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -610,12 +694,14 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 	}
 
 	if result.DeletedCount == 0 {
-		log.Printf("DeleteProjectHandler: No project matched for ID %s in category %s", id, categoryName)
+		log.Printf("DeleteProjectHandler: No project matched for ID %s in category %s", id, categorySlug)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Project not found or category does not match",
 		})
 	}
 
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityProject, objID, auditSummaryOf(project), nil, "")
+	publishEvent(models.EventProjectDeleted, userEmail, objID, category.ID, fiber.Map{"_id": objID, "slug": project.Slug})
 	log.Printf("DeleteProjectHandler: Project %s deleted successfully by user %s", id, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Project deleted successfully",
@@ -623,31 +709,74 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 }
 
 func GetProjectsByCategoryHandler(c *fiber.Ctx) error {
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("GetProjectsByCategoryHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("GetProjectsByCategoryHandler: Request to fetch projects in category %s", categoryName)
+	log.Printf("GetProjectsByCategoryHandler: Request to fetch projects in category %s", categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("GetProjectsByCategoryHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("GetProjectsByCategoryHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
 	}
+	if moved {
+		log.Printf("GetProjectsByCategoryHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
+	// Anonymous callers only see categories whose whole ancestry is visible
+	signedIn := isSignedIn(c)
+	if !signedIn {
+		public, err := isCategoryPublic(ctx, &category)
+		if err != nil {
+			log.Printf("GetProjectsByCategoryHandler: Failed to check visibility of category %s: %v", categorySlug, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch projects",
+			})
+		}
+		if !public {
+			log.Printf("GetProjectsByCategoryHandler: Category %s is hidden", categorySlug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+	}
+
+	locale := requestLocale(c)
+
+	// ?includeDescendants=true also lists projects of all subcategories
+	categoryIDs, err := categoryIDsWithDescendants(ctx, &category, c.QueryBool("includeDescendants"), signedIn)
+	if err != nil {
+		log.Printf("GetProjectsByCategoryHandler: Failed to resolve subcategories of %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch projects",
+		})
+	}
+
+	filter := bson.M{"category_id": bson.M{"$in": categoryIDs}}
+	if !signedIn {
+		filter["published"] = true
+	}
+	if status, msg := applyTagFilter(c, filter); status != 0 {
+		log.Printf("GetProjectsByCategoryHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
 
-	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(bson.M{"createdAt": -1}))
+	cursor, err := configs.ProjectsColl.Find(ctx, filter, options.Find().SetSort(projectSort))
 	if err != nil {
-		log.Printf("GetProjectsByCategoryHandler: Failed to fetch projects for category %s: %v", categoryName, err)
+		log.Printf("GetProjectsByCategoryHandler: Failed to fetch projects for category %s: %v", categorySlug, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch projects",
 		})
@@ -667,16 +796,26 @@ func GetProjectsByCategoryHandler(c *fiber.Ctx) error {
 			log.Printf("GetProjectsByCategoryHandler: Project has invalid ID (zero ObjectID), skipping")
 			continue
 		}
+		project = project.Localized(locale)
 
 		// Initialize project data
 		projectData := map[string]interface{}{
-			"_id":        project.ID.Hex(),
-			"imageUrl":   project.ImageUrl,
-			"videoUrl":   project.VideoUrl,
-			"category_id": project.CategoryID.Hex(),
-			"createdAt":  project.CreatedAt,
-			"updatedAt":  project.UpdatedAt,
-			"mediaType":  "", // Default empty
+			"_id":          project.ID.Hex(),
+			"slug":         project.Slug,
+			"title":        project.Title,
+			"description":  project.Description,
+			"translations": project.Translations,
+			"imageUrl":     project.ImageUrl,
+			"videoUrl":     project.VideoUrl,
+			"category_id":  project.CategoryID.Hex(),
+			"createdAt":    project.CreatedAt,
+			"updatedAt":    project.UpdatedAt,
+			"version":      project.Version,
+			"position":     project.Position,
+			"pinned":       project.Pinned,
+			"published":    project.Published,
+			"tags":         project.Tags,
+			"mediaType":    "", // Default empty
 		}
 
 		// Determine media type based on available URLs
@@ -738,12 +877,26 @@ func GetProjectsByCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	log.Printf("GetProjectsByCategoryHandler: Retrieved %d projects for category %s", len(projects), categoryName)
-	return c.JSON(fiber.Map{
+	response := fiber.Map{
 		"message": "Projects retrieved successfully",
 		"data":    projects,
 		"count":   len(projects),
-	})
+	}
+
+	// ?facets=true adds per-tag counts for the current filter, used for filter chips
+	if c.QueryBool("facets") {
+		facets, err := projectTagFacets(ctx, filter)
+		if err != nil {
+			log.Printf("GetProjectsByCategoryHandler: Failed to count tag facets: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch projects",
+			})
+		}
+		response["facets"] = facets
+	}
+
+	log.Printf("GetProjectsByCategoryHandler: Retrieved %d projects for category %s", len(projects), categorySlug)
+	return c.JSON(response)
 }
 
 func GetAllProjectsHandler(c *fiber.Ctx) error {
@@ -752,7 +905,31 @@ func GetAllProjectsHandler(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"createdAt": -1}))
+	// Unpublished projects and projects of hidden categories are only listed for signed-in admins
+	filter := bson.M{}
+	if !isSignedIn(c) {
+		categories, err := loadAllCategories(ctx)
+		if err != nil {
+			log.Printf("GetAllProjectsHandler: Failed to fetch categories: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch projects",
+			})
+		}
+		visibleIDs := []primitive.ObjectID{}
+		for _, category := range models.VisibleCategories(categories) {
+			visibleIDs = append(visibleIDs, category.ID)
+		}
+		filter["published"] = true
+		filter["category_id"] = bson.M{"$in": visibleIDs}
+	}
+	if status, msg := applyTagFilter(c, filter); status != 0 {
+		log.Printf("GetAllProjectsHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	cursor, err := configs.ProjectsColl.Find(ctx, filter, options.Find().SetSort(allProjectsSort))
 	if err != nil {
 		log.Printf("GetAllProjectsHandler: Failed to fetch projects: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -769,10 +946,28 @@ func GetAllProjectsHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	log.Printf("GetAllProjectsHandler: Retrieved %d projects", len(projects))
-	return c.JSON(fiber.Map{
+	locale := requestLocale(c)
+	for i := range projects {
+		projects[i] = projects[i].Localized(locale)
+	}
+
+	response := fiber.Map{
 		"message": "Projects retrieved successfully",
 		"data":    projects,
 		"count":   len(projects),
-	})
-}
\ No newline at end of file
+	}
+
+	if c.QueryBool("facets") {
+		facets, err := projectTagFacets(ctx, filter)
+		if err != nil {
+			log.Printf("GetAllProjectsHandler: Failed to count tag facets: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch projects",
+			})
+		}
+		response["facets"] = facets
+	}
+
+	log.Printf("GetAllProjectsHandler: Retrieved %d projects", len(projects))
+	return c.JSON(response)
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ProjectMedia describes one media item of a project. Uploaded files carry their stored
+// metadata; YouTube videos carry the embed and thumbnail URLs the players need.
+type ProjectMedia struct {
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Filename     string `json:"filename,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	EmbedUrl     string `json:"embedUrl,omitempty"`
+	ThumbnailUrl string `json:"thumbnailUrl,omitempty"`
+	// Variants maps variant names (thumbnail, medium, large) to scaled down copies of an uploaded image
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+// ProjectNeighbor is the short form of the previous and next project in a category.
+type ProjectNeighbor struct {
+	ID       string `json:"_id"`
+	Slug     string `json:"slug,omitempty"`
+	Title    string `json:"title"`
+	ImageUrl string `json:"imageUrl,omitempty"`
+}
+
+// uniqueProjectSlug derives a slug that is free within the category, adding a numeric
+// suffix when needed. exclude is the project being saved, so it does not collide with itself.
+func uniqueProjectSlug(ctx context.Context, categoryID primitive.ObjectID, base string, exclude primitive.ObjectID) (string, error) {
+	base = models.Slugify(base)
+	if base == "" {
+		return "", nil
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		count, err := configs.ProjectsColl.CountDocuments(ctx, bson.M{"category_id": categoryID, "slug": slug, "_id": bson.M{"$ne": exclude}})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// projectMedia lists the project's media and picks the mediaType reported by the
+// listing endpoints: the video wins over the image, as in GetProjectsByCategoryHandler.
+func projectMedia(ctx context.Context, project *models.Project) (string, []ProjectMedia) {
+	mediaType := ""
+	media := []ProjectMedia{}
+	filesColl := configs.Client.Database("ProjectsDB").Collection("FilesColl")
+
+	for _, item := range []struct{ kind, url string }{{"video", project.VideoUrl}, {"image", project.ImageUrl}} {
+		if item.url == "" {
+			continue
+		}
+
+		if videoID := models.YouTubeVideoID(item.url); videoID != "" {
+			media = append(media, ProjectMedia{
+				Kind:         "youtube",
+				Url:          item.url,
+				EmbedUrl:     "https://www.youtube.com/embed/" + videoID,
+				ThumbnailUrl: "https://img.youtube.com/vi/" + videoID + "/hqdefault.jpg",
+			})
+			if mediaType == "" {
+				mediaType = "youtube"
+			}
+			continue
+		}
+
+		entry := ProjectMedia{Kind: item.kind, Url: item.url}
+		if fileID, ok := mediaFileID(item.url); ok {
+			var file struct {
+				Filename string `bson:"filename"`
+				Type     string `bson:"type"`
+				Size     int64  `bson:"size"`
+			}
+			if err := filesColl.FindOne(ctx, bson.M{"_id": fileID}).Decode(&file); err == nil {
+				entry.Kind = file.Type
+				entry.Filename = file.Filename
+				entry.Size = file.Size
+				if entry.Kind == "image" {
+					entry.Variants = imageVariantURLs(item.url)
+				}
+			} else {
+				log.Printf("projectMedia: Failed to find file metadata for file ID %s: %v", fileID.Hex(), err)
+			}
+		}
+		media = append(media, entry)
+		if mediaType == "" {
+			mediaType = entry.Kind
+		}
+	}
+	return mediaType, media
+}
+
+// projectNeighbors finds the projects before and after the given one in the category's
+// display order, skipping unpublished projects unless includeUnpublished is set.
+func projectNeighbors(ctx context.Context, project *models.Project, includeUnpublished bool, locale string) (*ProjectNeighbor, *ProjectNeighbor, error) {
+	filter := bson.M{"category_id": project.CategoryID}
+	if !includeUnpublished {
+		filter["published"] = true
+	}
+	cursor, err := configs.ProjectsColl.Find(ctx, filter, options.Find().
+		SetSort(projectSort).
+		SetProjection(bson.M{"_id": 1, "slug": 1, "title": 1, "translations": 1, "imageUrl": 1}))
+	if err != nil {
+		return nil, nil, err
+	}
+	var siblings []models.Project
+	if err := cursor.All(ctx, &siblings); err != nil {
+		return nil, nil, err
+	}
+
+	neighbor := func(p models.Project) *ProjectNeighbor {
+		p = p.Localized(locale)
+		return &ProjectNeighbor{ID: p.ID.Hex(), Slug: p.Slug, Title: p.Title, ImageUrl: p.ImageUrl}
+	}
+	for i := range siblings {
+		if siblings[i].ID != project.ID {
+			continue
+		}
+		var previous, next *ProjectNeighbor
+		if i > 0 {
+			previous = neighbor(siblings[i-1])
+		}
+		if i < len(siblings)-1 {
+			next = neighbor(siblings[i+1])
+		}
+		return previous, next, nil
+	}
+	return nil, nil, nil
+}
+
+// projectLookupFilter finds a project of the category by ID or slug. A valid ObjectID is
+// looked up as an ID first; slugs never collide with one in practice. Slugs are decoded
+// from the URL param first so Thai slugs match.
+func projectLookupFilter(categoryID primitive.ObjectID, idOrSlug string) bson.M {
+	if objID, err := primitive.ObjectIDFromHex(idOrSlug); err == nil {
+		return bson.M{"category_id": categoryID, "$or": bson.A{bson.M{"_id": objID}, bson.M{"slug": idOrSlug}}}
+	}
+	return bson.M{"category_id": categoryID, "slug": models.SlugFromParam(idOrSlug)}
+}
+
+func GetProjectHandler(c *fiber.Ctx) error {
+	categorySlug := c.Params("category")
+	idOrSlug := c.Params("idOrSlug")
+	if categorySlug == "" || idOrSlug == "" {
+		log.Printf("GetProjectHandler: Category or project is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category and project are required",
+		})
+	}
+
+	log.Printf("GetProjectHandler: Request to fetch project %s in category %s", idOrSlug, categorySlug)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category models.Category
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("GetProjectHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	if moved {
+		log.Printf("GetProjectHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
+
+	// Anonymous callers only see categories whose whole ancestry is visible
+	signedIn := isSignedIn(c)
+	if !signedIn {
+		public, err := isCategoryPublic(ctx, &category)
+		if err != nil {
+			log.Printf("GetProjectHandler: Failed to check visibility of category %s: %v", categorySlug, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch project",
+			})
+		}
+		if !public {
+			log.Printf("GetProjectHandler: Category %s is hidden", categorySlug)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+	}
+
+	var project models.Project
+	err = configs.ProjectsColl.FindOne(ctx, projectLookupFilter(category.ID, idOrSlug)).Decode(&project)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("GetProjectHandler: Failed to fetch project %s: %v", idOrSlug, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch project",
+			})
+		}
+		log.Printf("GetProjectHandler: Project %s not found in category %s", idOrSlug, categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found",
+		})
+	}
+	if !project.Published && !signedIn {
+		log.Printf("GetProjectHandler: Project %s is unpublished", idOrSlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found",
+		})
+	}
+
+	locale := requestLocale(c)
+	project = project.Localized(locale)
+	category = category.Localized(locale)
+
+	mediaType, media := projectMedia(ctx, &project)
+
+	previous, next, err := projectNeighbors(ctx, &project, signedIn, locale)
+	if err != nil {
+		log.Printf("GetProjectHandler: Failed to fetch neighbors of project %s: %v", project.ID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch project",
+		})
+	}
+
+	projectData := fiber.Map{
+		"_id":          project.ID.Hex(),
+		"slug":         project.Slug,
+		"title":        project.Title,
+		"description":  project.Description,
+		"translations": project.Translations,
+		"tags":         project.Tags,
+		"imageUrl":     project.ImageUrl,
+		"videoUrl":     project.VideoUrl,
+		"category_id":  project.CategoryID.Hex(),
+		"createdAt":    project.CreatedAt,
+		"updatedAt":    project.UpdatedAt,
+		"version":      project.Version,
+		"position":     project.Position,
+		"pinned":       project.Pinned,
+		"published":    project.Published,
+		"mediaType":    mediaType,
+		"media":        media,
+		"category": fiber.Map{
+			"_id":          category.ID.Hex(),
+			"nameCategory": category.NameCategory,
+			"slug":         category.Slug,
+			"parentId":     category.ParentID,
+		},
+		"previous": previous,
+		"next":     next,
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(project.Version))
+	log.Printf("GetProjectHandler: Retrieved project %s in category %s", project.ID.Hex(), categorySlug)
+	return c.JSON(fiber.Map{
+		"message": "Project retrieved successfully",
+		"data":    projectData,
+	})
+}
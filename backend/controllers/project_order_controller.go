@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// projectSort orders projects within a category: pinned first, then the manual position, newest first for ties.
+var projectSort = bson.D{
+	{Key: "pinned", Value: -1},
+	{Key: "position", Value: 1},
+	{Key: "createdAt", Value: -1},
+}
+
+// allProjectsSort is used across categories, where positions are not comparable.
+var allProjectsSort = bson.D{
+	{Key: "pinned", Value: -1},
+	{Key: "createdAt", Value: -1},
+}
+
+type ProjectOrderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type ProjectPinRequest struct {
+	Pinned *bool `json:"pinned"`
+}
+
+func ReorderProjectsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("ReorderProjectsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("ReorderProjectsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
+		log.Printf("ReorderProjectsHandler: Category is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category is required",
+		})
+	}
+
+	log.Printf("ReorderProjectsHandler: User %s requested to reorder projects in category %s", userEmail, categorySlug)
+
+	var req ProjectOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ReorderProjectsHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(req.IDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, raw := range req.IDs {
+		objID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			log.Printf("ReorderProjectsHandler: Invalid project ID %s: %v", raw, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid project ID %s", raw),
+			})
+		}
+		if seen[objID] {
+			log.Printf("ReorderProjectsHandler: Duplicate project ID %s", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Duplicate project ID %s", raw),
+			})
+		}
+		seen[objID] = true
+		ids = append(ids, objID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var category models.Category
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("ReorderProjectsHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("ReorderProjectsHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	errIncompleteOrder := fmt.Errorf("order must list every project of the category exactly once")
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		// The list must be a full permutation of the category's projects
+		count, err := configs.ProjectsColl.CountDocuments(sessionContext, bson.M{"category_id": category.ID})
+		if err != nil {
+			return nil, err
+		}
+		matched, err := configs.ProjectsColl.CountDocuments(sessionContext, bson.M{"category_id": category.ID, "_id": bson.M{"$in": ids}})
+		if err != nil {
+			return nil, err
+		}
+		if int(count) != len(ids) || int(matched) != len(ids) {
+			return nil, errIncompleteOrder
+		}
+
+		// Only projects that actually move get a new version, so editors of the others keep a valid ETag
+		for position, id := range ids {
+			_, err := configs.ProjectsColl.UpdateOne(sessionContext,
+				bson.M{"_id": id, "category_id": category.ID, "position": bson.M{"$ne": position}},
+				bson.M{"$set": bson.M{"position": position}, "$inc": bson.M{"version": 1}},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+
+	if err != nil {
+		if err == errIncompleteOrder {
+			log.Printf("ReorderProjectsHandler: Incomplete order for category %s", categorySlug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Order must list every project of the category exactly once",
+			})
+		}
+		log.Printf("ReorderProjectsHandler: Transaction failed for category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reorder projects",
+		})
+	}
+
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(projectSort))
+	if err != nil {
+		log.Printf("ReorderProjectsHandler: Failed to fetch reordered projects for category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch projects",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("ReorderProjectsHandler: Failed to process projects: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process projects",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionReorder, models.AuditEntityCategory, category.ID, nil, bson.M{"projects": ids}, fmt.Sprintf("Reordered %d projects", len(ids)))
+	publishEvent(models.EventCategoryUpdated, userEmail, category.ID, category.ID, fiber.Map{"_id": category.ID, "projects": ids})
+	log.Printf("ReorderProjectsHandler: Reordered %d projects in category %s by user %s", len(ids), categorySlug, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Projects reordered successfully",
+		"data":    projects,
+		"count":   len(projects),
+	})
+}
+
+func SetProjectPinnedHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("SetProjectPinnedHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("SetProjectPinnedHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
+		log.Printf("SetProjectPinnedHandler: Category is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category is required",
+		})
+	}
+
+	log.Printf("SetProjectPinnedHandler: User %s requested to change pin of project ID %s in category %s", userEmail, id, categorySlug)
+
+	var req ProjectPinRequest
+	if err := c.BodyParser(&req); err != nil || req.Pinned == nil {
+		log.Printf("SetProjectPinnedHandler: Missing or invalid pinned field: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Pinned field is required",
+		})
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("SetProjectPinnedHandler: Invalid project ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category models.Category
+	_, err = findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("SetProjectPinnedHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	if err := ensureBaselineRevision(ctx, configs.ProjectsColl, models.RevisionEntityProject, objID); err != nil {
+		log.Printf("SetProjectPinnedHandler: Failed to record baseline revision for project %s: %v", id, err)
+	}
+	before := auditSnapshot(ctx, configs.ProjectsColl, objID)
+	var updated models.Project
+	snapshot, err := decodeWithSnapshot(configs.ProjectsColl.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID, "category_id": category.ID},
+		bson.M{"$set": bson.M{"pinned": *req.Pinned, "updatedAt": time.Now()}, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	), &updated)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("SetProjectPinnedHandler: Failed to update project ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update project",
+		})
+	}
+	if err == mongo.ErrNoDocuments {
+		log.Printf("SetProjectPinnedHandler: No project matched for ID %s in category %s", id, categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found or category does not match",
+		})
+	}
+
+	if _, err := recordRevision(ctx, models.RevisionEntityProject, objID, snapshot, userEmail, nil); err != nil {
+		log.Printf("SetProjectPinnedHandler: Failed to record revision for project %s: %v", id, err)
+	}
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityProject, objID, before, auditSnapshot(ctx, configs.ProjectsColl, objID), "")
+	publishEvent(models.EventProjectUpdated, userEmail, objID, category.ID, fiber.Map{"_id": objID, "pinned": *req.Pinned})
+
+	log.Printf("SetProjectPinnedHandler: Project %s pinned=%t by user %s", id, *req.Pinned, userEmail)
+	c.Set(fiber.HeaderETag, formatETag(updated.Version))
+	return c.JSON(fiber.Map{
+		"message": "Project pin updated successfully",
+		"data": fiber.Map{
+			"_id":     id,
+			"pinned":  *req.Pinned,
+			"version": updated.Version,
+		},
+	})
+}
@@ -0,0 +1,368 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxProjectTransfer caps how many projects one bulk move or copy may touch.
+const maxProjectTransfer = 100
+
+// ProjectTransferRequest names the target category by slug. IDs is only read by the
+// bulk endpoints; the single-project endpoints take the ID from the URL.
+type ProjectTransferRequest struct {
+	TargetCategory string   `json:"targetCategory"`
+	IDs            []string `json:"ids"`
+}
+
+func MoveProjectHandler(c *fiber.Ctx) error {
+	return transferProjects(c, "MoveProjectHandler", false)
+}
+
+func MoveProjectsHandler(c *fiber.Ctx) error {
+	return transferProjects(c, "MoveProjectsHandler", false)
+}
+
+func CopyProjectHandler(c *fiber.Ctx) error {
+	return transferProjects(c, "CopyProjectHandler", true)
+}
+
+func CopyProjectsHandler(c *fiber.Ctx) error {
+	return transferProjects(c, "CopyProjectsHandler", true)
+}
+
+// transferProjects moves or copies projects from the URL's category to the target category.
+// Moved and copied projects are appended after the target's existing projects so its manual
+// order is kept; moves keep their media, copies get their own copy of uploaded files.
+func transferProjects(c *fiber.Ctx, handler string, copyProjects bool) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
+		log.Printf("%s: Category is missing", handler)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category is required",
+		})
+	}
+
+	var req ProjectTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("%s: Failed to parse request body: %v", handler, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if req.TargetCategory == "" {
+		log.Printf("%s: Target category is missing", handler)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Target category is required",
+		})
+	}
+
+	rawIDs := req.IDs
+	if id := c.Params("id"); id != "" {
+		rawIDs = []string{id}
+	}
+	if len(rawIDs) == 0 || len(rawIDs) > maxProjectTransfer {
+		log.Printf("%s: Got %d project IDs", handler, len(rawIDs))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Between 1 and %d project IDs are required", maxProjectTransfer),
+		})
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(rawIDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, raw := range rawIDs {
+		objID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			log.Printf("%s: Invalid project ID %s: %v", handler, raw, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid project ID %s", raw),
+			})
+		}
+		if !seen[objID] {
+			seen[objID] = true
+			ids = append(ids, objID)
+		}
+	}
+
+	log.Printf("%s: User %s requested to transfer %d projects from category %s to %s", handler, userEmail, len(ids), categorySlug, req.TargetCategory)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var source models.Category
+	if _, err := findCategoryBySlug(ctx, categorySlug, &source); err != nil {
+		log.Printf("%s: Category %s not found: %v", handler, categorySlug, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	var target models.Category
+	if _, err := findCategoryBySlug(ctx, req.TargetCategory, &target); err != nil {
+		log.Printf("%s: Target category %s not found: %v", handler, req.TargetCategory, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Target category not found",
+		})
+	}
+	if target.ID == source.ID && !copyProjects {
+		log.Printf("%s: Target category %s is the source category", handler, req.TargetCategory)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Target category must differ from the current category",
+		})
+	}
+
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "category_id": source.ID}, options.Find().SetSort(projectSort))
+	if err != nil {
+		log.Printf("%s: Failed to fetch projects: %v", handler, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch projects",
+		})
+	}
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("%s: Failed to process projects: %v", handler, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process projects",
+		})
+	}
+	if len(projects) != len(ids) {
+		log.Printf("%s: Found %d of %d projects in category %s", handler, len(projects), len(ids), categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found or category does not match",
+		})
+	}
+
+	var transferred []models.Project
+	var snapshots []bson.M
+	if copyProjects {
+		transferred, err = copyProjectsTo(ctx, handler, projects, &target)
+	} else {
+		transferred, snapshots, err = moveProjectsTo(ctx, handler, projects, &source, &target)
+	}
+	if err != nil {
+		if err == errProjectsChanged {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Projects were changed by someone else, reload and try again",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	for i, project := range transferred {
+		if copyProjects {
+			err = recordCreatedRevision(ctx, models.RevisionEntityProject, project.ID, project, userEmail)
+		} else {
+			_, err = recordRevision(ctx, models.RevisionEntityProject, project.ID, snapshots[i], userEmail, nil)
+		}
+		if err != nil {
+			log.Printf("%s: Failed to record revision for project %s: %v", handler, project.ID.Hex(), err)
+		}
+		if copyProjects {
+			recordAudit(c, userEmail, models.AuditActionCopy, models.AuditEntityProject, project.ID, nil, auditSummaryOf(project), fmt.Sprintf("Copied from category %s", source.Slug))
+			publishEvent(models.EventProjectCreated, userEmail, project.ID, target.ID, project)
+		} else {
+			recordAudit(c, userEmail, models.AuditActionMove, models.AuditEntityProject, project.ID,
+				bson.M{"category_id": source.ID}, bson.M{"category_id": target.ID}, fmt.Sprintf("Moved from category %s to %s", source.Slug, target.Slug))
+			publishEvent(models.EventProjectUpdated, userEmail, project.ID, target.ID, project)
+		}
+	}
+
+	action := "moved"
+	if copyProjects {
+		action = "copied"
+	}
+	log.Printf("%s: %d projects %s from category %s to %s by user %s", handler, len(transferred), action, source.Slug, target.Slug, userEmail)
+	return c.JSON(fiber.Map{
+		"message":        fmt.Sprintf("Projects %s successfully", action),
+		"data":           transferred,
+		"count":          len(transferred),
+		"targetCategory": target.Slug,
+	})
+}
+
+var errProjectsChanged = fmt.Errorf("projects were changed during the transfer")
+
+// moveProjectsTo re-categorizes projects in one transaction; media URLs stay as they are.
+// It returns the moved projects with the revision snapshot of each.
+func moveProjectsTo(ctx context.Context, handler string, projects []models.Project, source, target *models.Category) ([]models.Project, []bson.M, error) {
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("%s: Failed to start session: %v", handler, err)
+		return nil, nil, fmt.Errorf("Failed to start transaction")
+	}
+	defer session.EndSession(ctx)
+
+	now := time.Now()
+	var moved []models.Project
+	var snapshots []bson.M
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		// A retried transaction starts again from the projects as they were read
+		moved = append([]models.Project(nil), projects...)
+		snapshots = make([]bson.M, len(moved))
+		position, err := nextSequence(sessionContext, configs.ProjectsColl, target.ID, "position", 0)
+		if err != nil {
+			return nil, err
+		}
+		for i := range moved {
+			if snapshots[i], err = relocateProject(sessionContext, &moved[i], target.ID, position+i, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if err == errProjectsChanged {
+			log.Printf("%s: A project left category %s during the move", handler, source.Slug)
+			return nil, nil, err
+		}
+		log.Printf("%s: Transaction failed moving projects to %s: %v", handler, target.Slug, err)
+		return nil, nil, fmt.Errorf("Failed to move projects")
+	}
+	return moved, snapshots, nil
+}
+
+// relocateProject moves one project into the target category at the given position. The
+// update is conditional on the project still being in the category it was read from. The
+// project is updated in place and its new state returned as a revision snapshot.
+func relocateProject(ctx context.Context, project *models.Project, targetID primitive.ObjectID, position int, now time.Time) (bson.M, error) {
+	// Slugs are unique per category, so a clash in the target gets a suffix
+	slug := project.Slug
+	if slug != "" {
+		var err error
+		slug, err = uniqueProjectSlug(ctx, targetID, slug, project.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := ensureBaselineRevision(ctx, configs.ProjectsColl, models.RevisionEntityProject, project.ID); err != nil {
+		return nil, err
+	}
+	set := bson.M{"category_id": targetID, "position": position, "updatedAt": now}
+	if slug != "" {
+		set["slug"] = slug
+	}
+	snapshot, err := decodeWithSnapshot(configs.ProjectsColl.FindOneAndUpdate(ctx,
+		bson.M{"_id": project.ID, "category_id": project.CategoryID},
+		bson.M{
+			"$set": set,
+			"$inc": bson.M{"version": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	), project)
+	if err == mongo.ErrNoDocuments {
+		return nil, errProjectsChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// copyProjectsTo inserts copies of projects with duplicated media. It is all or nothing:
+// when one copy fails, the copies and files created so far are removed again.
+func copyProjectsTo(ctx context.Context, handler string, projects []models.Project, target *models.Category) ([]models.Project, error) {
+	bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+	if err != nil {
+		log.Printf("%s: Failed to initialize GridFS: %v", handler, err)
+		return nil, fmt.Errorf("Failed to initialize GridFS")
+	}
+
+	position, err := nextSequence(ctx, configs.ProjectsColl, target.ID, "position", 0)
+	if err != nil {
+		log.Printf("%s: Failed to read positions of category %s: %v", handler, target.Slug, err)
+		return nil, fmt.Errorf("Failed to copy projects")
+	}
+
+	var copies []models.Project
+	rollback := func() {
+		for _, copied := range copies {
+			configs.ProjectsColl.DeleteOne(ctx, bson.M{"_id": copied.ID})
+			for _, fileUrl := range []string{copied.ImageUrl, copied.VideoUrl} {
+				if err := deleteGridFSFile(ctx, bucket, fileUrl); err != nil {
+					log.Printf("%s: Failed to remove copied file %s: %v", handler, fileUrl, err)
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	for i, project := range projects {
+		originalID := project.ID
+		copied := models.Project{
+			Title:        project.Title,
+			Description:  project.Description,
+			Translations: project.Translations,
+			CategoryID:   target.ID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			Version:      1,
+			Position:     position + i,
+			Published:    project.Published,
+			Tags:         project.Tags,
+			CopiedFrom:   &originalID,
+		}
+		if project.Slug != "" {
+			copied.Slug, err = uniqueProjectSlug(ctx, target.ID, project.Slug, primitive.NilObjectID)
+			if err != nil {
+				log.Printf("%s: Failed to derive slug for copy of project %s: %v", handler, originalID.Hex(), err)
+				rollback()
+				return nil, fmt.Errorf("Failed to copy projects")
+			}
+		}
+
+		copied.ImageUrl, err = copyGridFSFile(ctx, bucket, project.ImageUrl)
+		if err == nil {
+			copied.VideoUrl, err = copyGridFSFile(ctx, bucket, project.VideoUrl)
+			if err != nil {
+				deleteGridFSFile(ctx, bucket, copied.ImageUrl)
+			}
+		}
+		if err != nil {
+			log.Printf("%s: Failed to copy media of project %s: %v", handler, originalID.Hex(), err)
+			rollback()
+			return nil, fmt.Errorf("Failed to copy media of project %s", originalID.Hex())
+		}
+
+		result, err := configs.ProjectsColl.InsertOne(ctx, copied)
+		if err != nil {
+			log.Printf("%s: Failed to insert copy of project %s: %v", handler, originalID.Hex(), err)
+			deleteGridFSFile(ctx, bucket, copied.ImageUrl)
+			deleteGridFSFile(ctx, bucket, copied.VideoUrl)
+			rollback()
+			return nil, fmt.Errorf("Failed to copy projects")
+		}
+		copied.ID = result.InsertedID.(primitive.ObjectID)
+		copies = append(copies, copied)
+	}
+	return copies, nil
+}
@@ -0,0 +1,628 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxQuoteLimit = 100
+
+// QuoteItemRequest selects a service step and, optionally, some of its section texts as options.
+type QuoteItemRequest struct {
+	StepID  string   `json:"stepId"`
+	Options []string `json:"options"`
+}
+
+type QuoteStatusRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// AddQuoteRequestHandler takes the public quote form as multipart data: name, email, phone,
+// category, message, items (a JSON array of QuoteItemRequest) and up to three reference
+// files under "attachments". "website" is a honeypot like on the contact form.
+func AddQuoteRequestHandler(c *fiber.Ctx) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		log.Printf("AddQuoteRequestHandler: Failed to parse form data: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Failed to parse form data",
+		})
+	}
+	value := func(key string) string {
+		if len(form.Value[key]) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(form.Value[key][0])
+	}
+
+	if value("website") != "" {
+		log.Printf("AddQuoteRequestHandler: Dropped submission from %s caught by the honeypot", c.IP())
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message": "Quote request submitted successfully",
+		})
+	}
+
+	quote := models.QuoteRequest{
+		Name:    value("name"),
+		Email:   strings.ToLower(value("email")),
+		Phone:   value("phone"),
+		Message: models.NormalizeMultiline(value("message")),
+		Status:  models.QuoteStatusNew,
+	}
+	if err := models.ValidateContactDetails(quote.Name, quote.Email, quote.Phone); err != nil {
+		log.Printf("AddQuoteRequestHandler: Invalid submission: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if utf8.RuneCountInString(quote.Message) > models.MaxContactMessageLength {
+		log.Printf("AddQuoteRequestHandler: Message of %d characters is too long", utf8.RuneCountInString(quote.Message))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Message must be at most %d characters", models.MaxContactMessageLength),
+		})
+	}
+
+	var items []QuoteItemRequest
+	if err := json.Unmarshal([]byte(value("items")), &items); err != nil || len(items) == 0 || len(items) > models.MaxQuoteItems {
+		log.Printf("AddQuoteRequestHandler: Invalid items: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Between 1 and %d service steps must be selected", models.MaxQuoteItems),
+		})
+	}
+	files := form.File["attachments"]
+	if len(files) > models.MaxQuoteAttachments {
+		log.Printf("AddQuoteRequestHandler: Got %d attachments", len(files))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("At most %d reference files can be attached", models.MaxQuoteAttachments),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var category models.Category
+	if _, err := findCategoryBySlug(ctx, value("category"), &category); err != nil {
+		log.Printf("AddQuoteRequestHandler: Category %s not found: %v", value("category"), err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	// A category hidden itself or through an ancestor is unknown to the public form
+	public, err := isCategoryPublic(ctx, &category)
+	if err != nil {
+		log.Printf("AddQuoteRequestHandler: Failed to check visibility of category %s: %v", value("category"), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to submit quote request",
+		})
+	}
+	if !public {
+		log.Printf("AddQuoteRequestHandler: Category %s is hidden", value("category"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	quote.CategoryID = category.ID
+
+	quote.Items, err = resolveQuoteItems(ctx, category.ID, items)
+	if err != nil {
+		log.Printf("AddQuoteRequestHandler: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+	if err != nil {
+		log.Printf("AddQuoteRequestHandler: Failed to initialize GridFS: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to initialize GridFS",
+		})
+	}
+	removeAttachments := func() {
+		for _, attachment := range quote.Attachments {
+			if err := deleteGridFSFileByID(ctx, bucket, attachment.FileID); err != nil {
+				log.Printf("AddQuoteRequestHandler: Failed to remove attachment %s: %v", attachment.FileID.Hex(), err)
+			}
+		}
+	}
+	for _, file := range files {
+		attachment, err := uploadQuoteFile(ctx, file, "reference")
+		if err != nil {
+			log.Printf("AddQuoteRequestHandler: Failed to upload attachment %s: %v", file.Filename, err)
+			removeAttachments()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		quote.Attachments = append(quote.Attachments, attachment)
+	}
+
+	quote.CreatedAt = time.Now()
+	quote.Version = 1
+	result, err := configs.QuotesColl.InsertOne(ctx, quote)
+	if err != nil {
+		log.Printf("AddQuoteRequestHandler: Failed to save quote request from %s: %v", quote.Email, err)
+		removeAttachments()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to submit quote request",
+		})
+	}
+
+	quote.ID = result.InsertedID.(primitive.ObjectID)
+	publishEvent(models.EventQuoteCreated, "", quote.ID, category.ID, quote)
+
+	log.Printf("AddQuoteRequestHandler: Quote request %s received from %s for category %s", quote.ID.Hex(), quote.Email, category.Slug)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Quote request submitted successfully",
+	})
+}
+
+// resolveQuoteItems checks that every selected step belongs to the category and that the
+// options are texts of its sections, and copies the step titles into the items.
+func resolveQuoteItems(ctx context.Context, categoryID primitive.ObjectID, items []QuoteItemRequest) ([]models.QuoteItem, error) {
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		objID, err := primitive.ObjectIDFromHex(item.StepID)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid service step ID %s", item.StepID)
+		}
+		ids = append(ids, objID)
+	}
+
+	cursor, err := configs.ServiceStepsColl.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "category_id": categoryID})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch service steps")
+	}
+	var steps []models.ServiceStep
+	if err := cursor.All(ctx, &steps); err != nil {
+		return nil, fmt.Errorf("Failed to process service steps")
+	}
+	stepsByID := map[primitive.ObjectID]models.ServiceStep{}
+	for _, step := range steps {
+		stepsByID[step.ID] = step
+	}
+
+	resolved := make([]models.QuoteItem, 0, len(items))
+	seen := map[primitive.ObjectID]bool{}
+	for i, item := range items {
+		step, ok := stepsByID[ids[i]]
+		if !ok {
+			return nil, fmt.Errorf("Service step %s not found in this category", item.StepID)
+		}
+		if seen[step.ID] {
+			continue
+		}
+		seen[step.ID] = true
+
+		known := map[string]bool{}
+		for _, section := range step.Sections {
+			known[section.Text] = true
+		}
+		quoteItem := models.QuoteItem{StepID: step.ID, Title: step.Title}
+		for _, option := range item.Options {
+			option = strings.TrimSpace(option)
+			if !known[option] {
+				return nil, fmt.Errorf("Unknown option %q for service step %s", option, step.Title)
+			}
+			quoteItem.Options = append(quoteItem.Options, option)
+		}
+		resolved = append(resolved, quoteItem)
+	}
+	return resolved, nil
+}
+
+// uploadQuoteFile stores a file through the regular upload pipeline under a private upload type.
+func uploadQuoteFile(ctx context.Context, file *multipart.FileHeader, fileType string) (models.QuoteFile, error) {
+	fileUrl, err := uploadToGridFS(ctx, file, fileType)
+	if err != nil {
+		return models.QuoteFile{}, err
+	}
+	fileID, _ := mediaFileID(fileUrl)
+	return models.QuoteFile{FileID: fileID, Filename: file.Filename, Size: file.Size}, nil
+}
+
+func GetQuoteRequestsHandler(c *fiber.Ctx) error {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		if !models.IsQuoteStatus(status) {
+			log.Printf("GetQuoteRequestsHandler: Invalid status %s", status)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid status",
+			})
+		}
+		filter["status"] = status
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxQuoteLimit {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if categorySlug := c.Query("category"); categorySlug != "" {
+		var category models.Category
+		if _, err := findCategoryBySlug(ctx, categorySlug, &category); err != nil {
+			log.Printf("GetQuoteRequestsHandler: Category %s not found: %v", categorySlug, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		filter["category_id"] = category.ID
+	}
+
+	total, err := configs.QuotesColl.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("GetQuoteRequestsHandler: Failed to count quote requests: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch quote requests",
+		})
+	}
+
+	// Oldest first, so the queue is worked through in the order requests came in
+	cursor, err := configs.QuotesColl.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"createdAt": 1}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		log.Printf("GetQuoteRequestsHandler: Failed to fetch quote requests: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch quote requests",
+		})
+	}
+	quotes := []models.QuoteRequest{}
+	if err := cursor.All(ctx, &quotes); err != nil {
+		log.Printf("GetQuoteRequestsHandler: Failed to process quote requests: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process quote requests",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quote requests fetched successfully",
+		"data":    quotes,
+		"count":   len(quotes),
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+func GetQuoteRequestHandler(c *fiber.Ctx) error {
+	objID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		log.Printf("GetQuoteRequestHandler: Invalid quote request ID %s: %v", c.Params("id"), err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid quote request ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var quote models.QuoteRequest
+	if err := configs.QuotesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&quote); err != nil {
+		log.Printf("GetQuoteRequestHandler: Quote request %s not found: %v", objID.Hex(), err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Quote request not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quote request fetched successfully",
+		"data":    quote,
+	})
+}
+
+func UpdateQuoteStatusHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateQuoteStatusHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateQuoteStatusHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("UpdateQuoteStatusHandler: Invalid quote request ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid quote request ID",
+		})
+	}
+
+	var req QuoteStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateQuoteStatusHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if !models.IsQuoteStatus(req.Status) {
+		log.Printf("UpdateQuoteStatusHandler: Invalid status %s", req.Status)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid status",
+		})
+	}
+
+	log.Printf("UpdateQuoteStatusHandler: User %s requested to set quote request %s to %s", userEmail, id, req.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var quote models.QuoteRequest
+	if err := configs.QuotesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&quote); err != nil {
+		log.Printf("UpdateQuoteStatusHandler: Quote request %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Quote request not found",
+		})
+	}
+	if !models.CanTransitionQuote(quote.Status, req.Status) {
+		log.Printf("UpdateQuoteStatusHandler: Cannot move quote request %s from %s to %s", id, quote.Status, req.Status)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fmt.Sprintf("Cannot change status from %s to %s", quote.Status, req.Status),
+		})
+	}
+	// A request is only quoted once a quote document has been sent
+	if req.Status == models.QuoteStatusQuoted && quote.Quote == nil {
+		log.Printf("UpdateQuoteStatusHandler: Quote request %s has no quote document", id)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Upload a quote document before marking the request as quoted",
+		})
+	}
+
+	updated, err := transitionQuote(ctx, &quote, req.Status, userEmail, models.NormalizeMultiline(req.Note), nil)
+	if err != nil {
+		return quoteUpdateError(c, "UpdateQuoteStatusHandler", id, err)
+	}
+
+	log.Printf("UpdateQuoteStatusHandler: Quote request %s moved from %s to %s by user %s", id, quote.Status, req.Status, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Quote request updated successfully",
+		"data":    updated,
+	})
+}
+
+// SendQuoteHandler attaches a priced quote document (multipart: file, amount, currency,
+// validUntil as YYYY-MM-DD, note) and marks the request as quoted. Sending again while
+// quoted replaces the previous document.
+func SendQuoteHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("SendQuoteHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("SendQuoteHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("SendQuoteHandler: Invalid quote request ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid quote request ID",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("SendQuoteHandler: Failed to get file from form: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Cannot get file from form",
+		})
+	}
+	amount, err := strconv.ParseFloat(c.FormValue("amount"), 64)
+	if err != nil || amount <= 0 {
+		log.Printf("SendQuoteHandler: Invalid amount %q", c.FormValue("amount"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Amount must be a positive number",
+		})
+	}
+	document := models.QuoteDocument{
+		Amount:   amount,
+		Currency: strings.ToUpper(strings.TrimSpace(c.FormValue("currency", "THB"))),
+		Note:     models.NormalizeMultiline(c.FormValue("note")),
+		SentBy:   userEmail,
+		SentAt:   time.Now(),
+	}
+	if len(document.Currency) != 3 {
+		log.Printf("SendQuoteHandler: Invalid currency %q", document.Currency)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Currency must be a three-letter code",
+		})
+	}
+	if raw := c.FormValue("validUntil"); raw != "" {
+		validUntil, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			log.Printf("SendQuoteHandler: Invalid validUntil %q: %v", raw, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "validUntil must be a date like 2024-12-31",
+			})
+		}
+		document.ValidUntil = &validUntil
+	}
+
+	log.Printf("SendQuoteHandler: User %s requested to send a quote for request %s", userEmail, id)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var quote models.QuoteRequest
+	if err := configs.QuotesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&quote); err != nil {
+		log.Printf("SendQuoteHandler: Quote request %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Quote request not found",
+		})
+	}
+	if quote.Status != models.QuoteStatusReviewing && quote.Status != models.QuoteStatusQuoted {
+		log.Printf("SendQuoteHandler: Quote request %s is %s", id, quote.Status)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fmt.Sprintf("Quotes can only be sent for requests under review, this one is %s", quote.Status),
+		})
+	}
+
+	document.File, err = uploadQuoteFile(ctx, file, "quote")
+	if err != nil {
+		log.Printf("SendQuoteHandler: Failed to upload quote document %s: %v", file.Filename, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	bucket, err := gridfs.NewBucket(configs.Client.Database("ProjectsDB"))
+	if err != nil {
+		log.Printf("SendQuoteHandler: Failed to initialize GridFS: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to initialize GridFS",
+		})
+	}
+
+	updated, err := transitionQuote(ctx, &quote, models.QuoteStatusQuoted, userEmail, document.Note, &document)
+	if err != nil {
+		if err := deleteGridFSFileByID(ctx, bucket, document.File.FileID); err != nil {
+			log.Printf("SendQuoteHandler: Failed to remove unused quote document: %v", err)
+		}
+		return quoteUpdateError(c, "SendQuoteHandler", id, err)
+	}
+
+	// The replaced document is no longer referenced by the request
+	if quote.Quote != nil {
+		if err := deleteGridFSFileByID(ctx, bucket, quote.Quote.File.FileID); err != nil {
+			log.Printf("SendQuoteHandler: Failed to remove previous quote document: %v", err)
+		}
+	}
+
+	log.Printf("SendQuoteHandler: Quote of %.2f %s sent for request %s by user %s", amount, document.Currency, id, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Quote sent successfully",
+		"data":    updated,
+	})
+}
+
+// GetQuoteFileHandler serves a reference file or the quote document of a request; these
+// are private and not available through /files.
+func GetQuoteFileHandler(c *fiber.Ctx) error {
+	objID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		log.Printf("GetQuoteFileHandler: Invalid quote request ID %s: %v", c.Params("id"), err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid quote request ID",
+		})
+	}
+	fileID, err := primitive.ObjectIDFromHex(c.Params("fileId"))
+	if err != nil {
+		log.Printf("GetQuoteFileHandler: Invalid file ID %s: %v", c.Params("fileId"), err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid file ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": objID, "$or": bson.A{
+		bson.M{"attachments.file_id": fileID},
+		bson.M{"quote.file.file_id": fileID},
+	}}
+	if err := configs.QuotesColl.FindOne(ctx, filter).Err(); err != nil {
+		log.Printf("GetQuoteFileHandler: File %s not found on quote request %s: %v", fileID.Hex(), objID.Hex(), err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	}
+
+	return streamGridFSFile(c, "GetQuoteFileHandler", fileID)
+}
+
+var errQuoteChanged = fmt.Errorf("quote request was changed by someone else")
+
+// transitionQuote moves a quote request to status, recording the change in its history.
+// The update only applies if the request is still at the version that was read.
+func transitionQuote(ctx context.Context, quote *models.QuoteRequest, status, userEmail, note string, document *models.QuoteDocument) (*models.QuoteRequest, error) {
+	now := time.Now()
+	set := bson.M{"status": status, "updatedAt": now}
+	if document != nil {
+		set["quote"] = document
+	}
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}
+	// Replacing a quote keeps the status, so only real changes go into the history
+	if status != quote.Status {
+		update["$push"] = bson.M{"statusHistory": models.QuoteStatusChange{
+			From:      quote.Status,
+			To:        status,
+			By:        userEmail,
+			Note:      note,
+			ChangedAt: now,
+		}}
+	}
+
+	var updated models.QuoteRequest
+	err := configs.QuotesColl.FindOneAndUpdate(ctx,
+		bson.M{"_id": quote.ID, "version": quote.Version},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, errQuoteChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func quoteUpdateError(c *fiber.Ctx, handler, id string, err error) error {
+	if err == errQuoteChanged {
+		log.Printf("%s: Quote request %s changed concurrently", handler, id)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Quote request was changed by someone else, reload and try again",
+		})
+	}
+	log.Printf("%s: Failed to update quote request %s: %v", handler, id, err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to update quote request",
+	})
+}
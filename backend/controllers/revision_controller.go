@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Fields that a restore never overwrites: identity, creation time, placement and the concurrency version stay as they are now.
+var revisionRestoreSkipFields = map[string]bool{
+	"_id":         true,
+	"createdAt":   true,
+	"category_id": true,
+	"position":    true,
+	"stepNumber":  true,
+	"version":     true,
+}
+
+// Fields a restore keeps when the snapshot does not have them. Snapshots taken before
+// publishing, slugs and tags existed would otherwise unpublish the project, break its
+// links and drop its tags; when the snapshot has them they are restored as usual.
+var revisionRestoreKeepFields = map[string]bool{
+	"published": true,
+	"slug":      true,
+	"tags":      true,
+}
+
+// maxRevisionAttempts bounds how often recordRevision retries when a concurrent save took
+// the same revision number.
+const maxRevisionAttempts = 5
+
+// recordRevision stores snapshot, the state a write left the entity in, as a new immutable
+// revision. The snapshot comes from the write itself (see decodeWithSnapshot and snapshotOf)
+// rather than a later read, so concurrent saves cannot record each other's state.
+// Numbers are read as the latest plus one and kept unique by an index, so a concurrent save
+// of the same entity makes the insert fail with a duplicate key; it is then retried with a
+// fresh number instead of losing the revision.
+func recordRevision(ctx context.Context, entityType string, entityID primitive.ObjectID, snapshot bson.M, authorEmail string, restoredFrom *primitive.ObjectID) (*models.Revision, error) {
+	var err error
+	for attempt := 0; attempt < maxRevisionAttempts; attempt++ {
+		var revision *models.Revision
+		revision, err = insertRevision(ctx, entityType, entityID, snapshot, authorEmail, restoredFrom)
+		if !mongo.IsDuplicateKeyError(err) {
+			return revision, err
+		}
+	}
+	return nil, err
+}
+
+// ensureBaselineRevision records the current state of an entity as its first revision when
+// it has none yet, which is the case for documents created before revisions existed. It
+// is called before the first tracked update so the original content stays restorable.
+// The baseline has no author.
+func ensureBaselineRevision(ctx context.Context, coll *mongo.Collection, entityType string, entityID primitive.ObjectID) error {
+	count, err := configs.RevisionsColl.CountDocuments(ctx,
+		bson.M{"entityType": entityType, "entityId": entityID},
+		options.Count().SetLimit(1),
+	)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	var snapshot bson.M
+	if err := coll.FindOne(ctx, bson.M{"_id": entityID}).Decode(&snapshot); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+	_, err = insertRevision(ctx, entityType, entityID, snapshot, "", nil)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent first edit recorded the baseline already
+		return nil
+	}
+	return err
+}
+
+// decodeWithSnapshot reads the document returned by a FindOneAndUpdate with
+// ReturnDocument After as a revision snapshot, decoding it into v as well when v is not nil.
+func decodeWithSnapshot(result *mongo.SingleResult, v interface{}) (bson.M, error) {
+	raw, err := result.Raw()
+	if err != nil {
+		return nil, err
+	}
+	var snapshot bson.M
+	if err := bson.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	if v != nil {
+		if err := bson.Unmarshal(raw, v); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// snapshotOf returns a document as an insert stores it, for the revision of a new entity.
+func snapshotOf(doc interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot bson.M
+	if err := bson.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// recordCreatedRevision records the first revision of an entity from the document that was inserted.
+func recordCreatedRevision(ctx context.Context, entityType string, entityID primitive.ObjectID, doc interface{}, authorEmail string) error {
+	snapshot, err := snapshotOf(doc)
+	if err != nil {
+		return err
+	}
+	_, err = recordRevision(ctx, entityType, entityID, snapshot, authorEmail, nil)
+	return err
+}
+
+func insertRevision(ctx context.Context, entityType string, entityID primitive.ObjectID, snapshot bson.M, authorEmail string, restoredFrom *primitive.ObjectID) (*models.Revision, error) {
+	number := 1
+	var latest models.Revision
+	err := configs.RevisionsColl.FindOne(ctx,
+		bson.M{"entityType": entityType, "entityId": entityID},
+		options.FindOne().SetSort(bson.M{"number": -1}),
+	).Decode(&latest)
+	if err == nil {
+		number = latest.Number + 1
+	} else if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	revision := models.Revision{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Number:       number,
+		Snapshot:     snapshot,
+		AuthorEmail:  authorEmail,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+
+	result, err := configs.RevisionsColl.InsertOne(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	revision.ID = result.InsertedID.(primitive.ObjectID)
+	return &revision, nil
+}
+
+// resolveRevisionEntity validates the URL params and returns the collection and ID of the entity whose history is requested.
+// Site settings are a single document and need no params.
+func resolveRevisionEntity(ctx context.Context, c *fiber.Ctx, entityType string) (*mongo.Collection, primitive.ObjectID, int, string) {
+	if entityType == models.RevisionEntitySiteSettings {
+		var settings models.SiteSettings
+		err := configs.SiteSettingsColl.FindOne(ctx, bson.M{"key": models.SiteSettingsKey}).Decode(&settings)
+		if err == mongo.ErrNoDocuments {
+			return nil, primitive.NilObjectID, fiber.StatusNotFound, "Site settings have not been saved yet"
+		}
+		if err != nil {
+			return nil, primitive.NilObjectID, fiber.StatusInternalServerError, "Failed to fetch entity"
+		}
+		return configs.SiteSettingsColl, settings.ID, 0, ""
+	}
+
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
+		return nil, primitive.NilObjectID, fiber.StatusBadRequest, "Category is required"
+	}
+
+	var category models.Category
+	if _, err := findCategoryBySlug(ctx, categorySlug, &category); err != nil {
+		return nil, primitive.NilObjectID, fiber.StatusBadRequest, "Category not found"
+	}
+
+	coll, param, notFound := configs.ProjectsColl, "id", "Project not found or category does not match"
+	if entityType == models.RevisionEntityServiceStep {
+		coll, param, notFound = configs.ServiceStepsColl, "stepId", "Service step not found"
+	}
+
+	entityID, err := primitive.ObjectIDFromHex(c.Params(param))
+	if err != nil {
+		return nil, primitive.NilObjectID, fiber.StatusBadRequest, "Invalid ID"
+	}
+
+	count, err := coll.CountDocuments(ctx, bson.M{"_id": entityID, "category_id": category.ID})
+	if err != nil {
+		return nil, primitive.NilObjectID, fiber.StatusInternalServerError, "Failed to fetch entity"
+	}
+	if count == 0 {
+		return nil, primitive.NilObjectID, fiber.StatusNotFound, notFound
+	}
+
+	return coll, entityID, 0, ""
+}
+
+func findRevisionByNumber(ctx context.Context, entityType string, entityID primitive.ObjectID, raw string) (*models.Revision, int, string) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number < 1 {
+		return nil, fiber.StatusBadRequest, "Invalid revision number"
+	}
+
+	var revision models.Revision
+	err = configs.RevisionsColl.FindOne(ctx, bson.M{"entityType": entityType, "entityId": entityID, "number": number}).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, fiber.StatusNotFound, "Revision not found"
+	}
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, "Failed to fetch revision"
+	}
+	return &revision, 0, ""
+}
+
+func listRevisions(c *fiber.Ctx, handler, entityType string) error {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, entityID, status, msg := resolveRevisionEntity(ctx, c, entityType)
+	if status != 0 {
+		log.Printf("%s: %s", handler, msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("%s: User %s requested revisions of %s %s", handler, userEmail, entityType, entityID.Hex())
+
+	cursor, err := configs.RevisionsColl.Find(ctx,
+		bson.M{"entityType": entityType, "entityId": entityID},
+		options.Find().SetSort(bson.M{"number": -1}),
+	)
+	if err != nil {
+		log.Printf("%s: Failed to fetch revisions for %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch revisions",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []models.Revision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		log.Printf("%s: Failed to process revisions: %v", handler, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process revisions",
+		})
+	}
+
+	log.Printf("%s: Retrieved %d revisions for %s %s", handler, len(revisions), entityType, entityID.Hex())
+	return c.JSON(fiber.Map{
+		"message": "Revisions retrieved successfully",
+		"data":    revisions,
+		"count":   len(revisions),
+	})
+}
+
+func diffRevisions(c *fiber.Ctx, handler, entityType string) error {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, entityID, status, msg := resolveRevisionEntity(ctx, c, entityType)
+	if status != 0 {
+		log.Printf("%s: %s", handler, msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("%s: User %s requested diff of %s %s revisions %s..%s", handler, userEmail, entityType, entityID.Hex(), c.Query("from"), c.Query("to"))
+
+	from, status, msg := findRevisionByNumber(ctx, entityType, entityID, c.Query("from"))
+	if status != 0 {
+		log.Printf("%s: From revision %q: %s", handler, c.Query("from"), msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+	to, status, msg := findRevisionByNumber(ctx, entityType, entityID, c.Query("to"))
+	if status != 0 {
+		log.Printf("%s: To revision %q: %s", handler, c.Query("to"), msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	changes := models.DiffSnapshots(from.Snapshot, to.Snapshot)
+	return c.JSON(fiber.Map{
+		"message": "Revision diff computed successfully",
+		"data": fiber.Map{
+			"from":    from.Number,
+			"to":      to.Number,
+			"changes": changes,
+		},
+	})
+}
+
+// upgradeServiceStepSnapshot converts the flat subtitles/headings of a service step
+// snapshot taken before steps stored sections, the same way migration
+// 0001_service_step_sections converted the steps themselves.
+func upgradeServiceStepSnapshot(set bson.M) error {
+	if _, ok := set["sections"]; ok {
+		return nil
+	}
+	raw, err := bson.Marshal(set)
+	if err != nil {
+		return err
+	}
+	var legacy struct {
+		Subtitles []string `bson:"subtitles"`
+		Headings  []string `bson:"headings"`
+	}
+	if err := bson.Unmarshal(raw, &legacy); err != nil {
+		return err
+	}
+	set["sections"] = models.SectionsFromFlat(legacy.Subtitles, legacy.Headings)
+	delete(set, "subtitles")
+	delete(set, "headings")
+	return nil
+}
+
+func restoreRevision(c *fiber.Ctx, handler, entityType string) error {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	coll, entityID, status, msg := resolveRevisionEntity(ctx, c, entityType)
+	if status != 0 {
+		log.Printf("%s: %s", handler, msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("%s: User %s requested to restore %s %s to revision %s", handler, userEmail, entityType, entityID.Hex(), c.Params("revision"))
+
+	source, status, msg := findRevisionByNumber(ctx, entityType, entityID, c.Params("revision"))
+	if status != 0 {
+		log.Printf("%s: Revision %q: %s", handler, c.Params("revision"), msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	set := bson.M{}
+	for field, value := range source.Snapshot {
+		if !revisionRestoreSkipFields[field] {
+			set[field] = value
+		}
+	}
+	if entityType == models.RevisionEntityServiceStep {
+		if err := upgradeServiceStepSnapshot(set); err != nil {
+			log.Printf("%s: Failed to convert revision %d of %s %s: %v", handler, source.Number, entityType, entityID.Hex(), err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to restore revision",
+			})
+		}
+	}
+	set["updatedAt"] = time.Now()
+
+	// Other fields added after the snapshot was taken are dropped so the restored document matches it.
+	var current bson.M
+	if err := coll.FindOne(ctx, bson.M{"_id": entityID}).Decode(&current); err != nil {
+		log.Printf("%s: Failed to fetch %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch entity",
+		})
+	}
+	unset := bson.M{}
+	for field := range current {
+		if _, ok := set[field]; !ok && !revisionRestoreSkipFields[field] && !revisionRestoreKeepFields[field] {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	before := auditSnapshot(ctx, coll, entityID)
+	snapshot, err := decodeWithSnapshot(coll.FindOneAndUpdate(ctx, bson.M{"_id": entityID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)), nil)
+	if err != nil {
+		log.Printf("%s: Failed to restore %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore revision",
+		})
+	}
+
+	revision, err := recordRevision(ctx, entityType, entityID, snapshot, userEmail, &source.ID)
+	if err != nil {
+		log.Printf("%s: Failed to record revision for %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record revision",
+		})
+	}
+
+	// Revision and audit entity types share their names
+	recordAudit(c, userEmail, models.AuditActionRestore, entityType, entityID, before, auditSnapshot(ctx, coll, entityID),
+		fmt.Sprintf("Restored from revision %d", source.Number))
+	categoryID, _ := current["category_id"].(primitive.ObjectID)
+	publishEvent(revisionEntityEvent(entityType), userEmail, entityID, categoryID, fiber.Map{"_id": entityID, "revision": revision.Number})
+
+	log.Printf("%s: %s %s restored from revision %d as revision %d by user %s", handler, entityType, entityID.Hex(), source.Number, revision.Number, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Revision restored successfully",
+		"data":    revision,
+	})
+}
+
+func GetProjectRevisionsHandler(c *fiber.Ctx) error {
+	return listRevisions(c, "GetProjectRevisionsHandler", models.RevisionEntityProject)
+}
+
+func DiffProjectRevisionsHandler(c *fiber.Ctx) error {
+	return diffRevisions(c, "DiffProjectRevisionsHandler", models.RevisionEntityProject)
+}
+
+func RestoreProjectRevisionHandler(c *fiber.Ctx) error {
+	return restoreRevision(c, "RestoreProjectRevisionHandler", models.RevisionEntityProject)
+}
+
+func GetServiceStepRevisionsHandler(c *fiber.Ctx) error {
+	return listRevisions(c, "GetServiceStepRevisionsHandler", models.RevisionEntityServiceStep)
+}
+
+func DiffServiceStepRevisionsHandler(c *fiber.Ctx) error {
+	return diffRevisions(c, "DiffServiceStepRevisionsHandler", models.RevisionEntityServiceStep)
+}
+
+func RestoreServiceStepRevisionHandler(c *fiber.Ctx) error {
+	return restoreRevision(c, "RestoreServiceStepRevisionHandler", models.RevisionEntityServiceStep)
+}
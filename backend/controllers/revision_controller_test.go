@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"backend/models"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUpgradeServiceStepSnapshot(t *testing.T) {
+	set := bson.M{
+		"title":     "Brief",
+		"subtitles": bson.A{"Goals", "Audience"},
+		"headings":  bson.A{"Who buys"},
+	}
+	if err := upgradeServiceStepSnapshot(set); err != nil {
+		t.Fatalf("upgradeServiceStepSnapshot: %v", err)
+	}
+	want := []models.Subtitle{
+		{Text: "Goals", Headings: []string{}},
+		{Text: "Audience", Headings: []string{"Who buys"}},
+	}
+	if got := set["sections"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v; want %v", got, want)
+	}
+	if _, ok := set["subtitles"]; ok {
+		t.Errorf("flat subtitles kept: %v", set)
+	}
+	if _, ok := set["headings"]; ok {
+		t.Errorf("flat headings kept: %v", set)
+	}
+
+	sections := bson.A{bson.M{"text": "Kept", "headings": bson.A{}}}
+	set = bson.M{"sections": sections}
+	if err := upgradeServiceStepSnapshot(set); err != nil {
+		t.Fatalf("upgradeServiceStepSnapshot: %v", err)
+	}
+	if !reflect.DeepEqual(set["sections"], sections) {
+		t.Errorf("sections snapshot changed to %v", set["sections"])
+	}
+}
+
+func TestSnapshotOfMatchesStoredDocument(t *testing.T) {
+	project := models.Project{Title: "Kitchen", Slug: "kitchen", Tags: []string{"wood"}, Version: 3}
+	snapshot, err := snapshotOf(project)
+	if err != nil {
+		t.Fatalf("snapshotOf: %v", err)
+	}
+	raw, err := bson.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var restored models.Project
+	if err := bson.Unmarshal(raw, &restored); err != nil {
+		t.Fatalf("bson.Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(restored, project) {
+		t.Errorf("restored = %+v; want %+v", restored, project)
+	}
+}
@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSearchQueryLength = 200
+	maxSearchLimit       = 50
+	// searchCandidates bounds how many matches per type are ranked before paginating
+	searchCandidates = 200
+	snippetRadius    = 60
+)
+
+type searchField struct {
+	Path   string
+	Weight float64
+}
+
+// Search fields and weights mirror the text indexes created in configs.InitDB; they
+// are also used to score substring matches for Thai queries.
+var (
+	projectSearchFields = []searchField{
+		{"title", 10}, {"translations.en.title", 10}, {"tags", 5},
+		{"description", 2}, {"translations.en.description", 2},
+	}
+	categorySearchFields = []searchField{
+		{"nameCategory", 10}, {"translations.en.nameCategory", 10},
+		{"description", 2}, {"translations.en.description", 2},
+	}
+	serviceStepSearchFields = []searchField{
+		{"title", 10}, {"translations.en.title", 10},
+		{"sections.text", 3}, {"translations.en.sections.text", 3},
+		{"sections.headings", 1}, {"translations.en.sections.headings", 1},
+	}
+)
+
+type SearchHit struct {
+	Type         string  `json:"type"`
+	ID           string  `json:"_id"`
+	Title        string  `json:"title"`
+	Snippet      string  `json:"snippet"`
+	Score        float64 `json:"score"`
+	Slug         string  `json:"slug,omitempty"`
+	CategoryID   string  `json:"category_id,omitempty"`
+	CategorySlug string  `json:"categorySlug,omitempty"`
+	ImageUrl     string  `json:"imageUrl,omitempty"`
+}
+
+type scoredDocument struct {
+	Doc   bson.M
+	Score float64
+}
+
+// searchValues collects the strings at a dotted path, descending into arrays such as sections.
+func searchValues(value interface{}, path []string) []string {
+	switch v := value.(type) {
+	case string:
+		if len(path) == 0 {
+			return []string{v}
+		}
+	case bson.M:
+		if len(path) > 0 {
+			return searchValues(v[path[0]], path[1:])
+		}
+	case bson.D:
+		if len(path) > 0 {
+			return searchValues(v.Map()[path[0]], path[1:])
+		}
+	case bson.A:
+		var values []string
+		for _, item := range v {
+			values = append(values, searchValues(item, path)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// findSearchMatches runs one search against a collection. Queries with Thai text match
+// every term as a case-insensitive substring and are scored by field weight; other
+// queries use the collection's text index and its relevance score.
+func findSearchMatches(ctx context.Context, coll *mongo.Collection, fields []searchField, filter bson.M, q string, terms []string) ([]scoredDocument, error) {
+	if models.ContainsThai(q) {
+		var and bson.A
+		for _, term := range terms {
+			var or bson.A
+			for _, field := range fields {
+				or = append(or, bson.M{field.Path: bson.M{"$regex": regexp.QuoteMeta(term), "$options": "i"}})
+			}
+			and = append(and, bson.M{"$or": or})
+		}
+		filter["$and"] = and
+
+		cursor, err := coll.Find(ctx, filter, options.Find().SetLimit(searchCandidates))
+		if err != nil {
+			return nil, err
+		}
+		var docs []bson.M
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, err
+		}
+
+		matches := make([]scoredDocument, 0, len(docs))
+		for _, doc := range docs {
+			score := 0.0
+			for _, field := range fields {
+				for _, value := range searchValues(doc, strings.Split(field.Path, ".")) {
+					lowered := strings.ToLower(value)
+					for _, term := range terms {
+						if strings.Contains(lowered, term) {
+							score += field.Weight
+						}
+					}
+				}
+			}
+			matches = append(matches, scoredDocument{Doc: doc, Score: score})
+		}
+		return matches, nil
+	}
+
+	filter["$text"] = bson.M{"$search": q}
+	score := bson.M{"$meta": "textScore"}
+	cursor, err := coll.Find(ctx, filter, options.Find().
+		SetProjection(bson.M{"searchScore": score}).
+		SetSort(bson.M{"searchScore": score}).
+		SetLimit(searchCandidates))
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	matches := make([]scoredDocument, 0, len(docs))
+	for _, doc := range docs {
+		s, _ := doc["searchScore"].(float64)
+		matches = append(matches, scoredDocument{Doc: doc, Score: s})
+	}
+	return matches, nil
+}
+
+// decodeSearchDocument converts a raw match into its model type.
+func decodeSearchDocument(doc bson.M, out interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
+
+// searchSnippet highlights the first text that contains a term, falling back to the
+// start of the first non-empty text.
+func searchSnippet(terms []string, texts ...string) string {
+	for _, text := range texts {
+		if snippet, ok := models.HighlightSnippet(text, terms, snippetRadius); ok {
+			return snippet
+		}
+	}
+	for _, text := range texts {
+		if text != "" {
+			return models.TruncateText(text, 2*snippetRadius)
+		}
+	}
+	return ""
+}
+
+func SearchHandler(c *fiber.Ctx) error {
+	q := models.NormalizeText(c.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > maxSearchQueryLength {
+		log.Printf("SearchHandler: Missing or too long query")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Query q is required and must be at most 200 characters",
+		})
+	}
+	terms := models.SearchTerms(q)
+	if len(terms) == 0 {
+		log.Printf("SearchHandler: Query %q has no search terms", q)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Query q is required and must be at most 200 characters",
+		})
+	}
+
+	types := map[string]bool{}
+	if raw := c.Query("type"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if t != models.SearchTypeProject && t != models.SearchTypeCategory && t != models.SearchTypeServiceStep {
+				log.Printf("SearchHandler: Invalid type %s", t)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Type must be project, category or serviceStep",
+				})
+			}
+			types[t] = true
+		}
+	} else {
+		types = map[string]bool{models.SearchTypeProject: true, models.SearchTypeCategory: true, models.SearchTypeServiceStep: true}
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxSearchLimit {
+		limit = 20
+	}
+
+	log.Printf("SearchHandler: Search for %q (page %d, limit %d)", q, page, limit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		log.Printf("SearchHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to search",
+		})
+	}
+
+	// Anonymous visitors only find published content of visible categories
+	signedIn := isSignedIn(c)
+	if !signedIn {
+		categories = models.VisibleCategories(categories)
+	}
+	categoriesByID := make(map[primitive.ObjectID]models.Category, len(categories))
+	categoryIDs := make([]primitive.ObjectID, 0, len(categories))
+	for _, category := range categories {
+		categoriesByID[category.ID] = category
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	locale := requestLocale(c)
+	hits := []SearchHit{}
+
+	if types[models.SearchTypeProject] {
+		filter := bson.M{"category_id": bson.M{"$in": categoryIDs}}
+		if !signedIn {
+			filter["published"] = true
+		}
+		matches, err := findSearchMatches(ctx, configs.ProjectsColl, projectSearchFields, filter, q, terms)
+		if err != nil {
+			log.Printf("SearchHandler: Failed to search projects: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to search",
+			})
+		}
+		for _, match := range matches {
+			var project models.Project
+			if err := decodeSearchDocument(match.Doc, &project); err != nil {
+				log.Printf("SearchHandler: Failed to decode project: %v", err)
+				continue
+			}
+			raw := project
+			project = project.Localized(locale)
+			en := raw.Translations["en"]
+			hits = append(hits, SearchHit{
+				Type:         models.SearchTypeProject,
+				ID:           project.ID.Hex(),
+				Title:        project.Title,
+				Snippet:      searchSnippet(terms, project.Description, project.Title, raw.Description, raw.Title, en.Description, en.Title),
+				Score:        match.Score,
+				Slug:         project.Slug,
+				CategoryID:   project.CategoryID.Hex(),
+				CategorySlug: categoriesByID[project.CategoryID].Slug,
+				ImageUrl:     project.ImageUrl,
+			})
+		}
+	}
+
+	if types[models.SearchTypeCategory] {
+		matches, err := findSearchMatches(ctx, configs.CategoriesColl, categorySearchFields, bson.M{"_id": bson.M{"$in": categoryIDs}}, q, terms)
+		if err != nil {
+			log.Printf("SearchHandler: Failed to search categories: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to search",
+			})
+		}
+		for _, match := range matches {
+			var category models.Category
+			if err := decodeSearchDocument(match.Doc, &category); err != nil {
+				log.Printf("SearchHandler: Failed to decode category: %v", err)
+				continue
+			}
+			raw := category
+			category = category.Localized(locale)
+			en := raw.Translations["en"]
+			hits = append(hits, SearchHit{
+				Type:         models.SearchTypeCategory,
+				ID:           category.ID.Hex(),
+				Title:        category.NameCategory,
+				Snippet:      searchSnippet(terms, category.Description, category.NameCategory, raw.Description, raw.NameCategory, en.Description, en.NameCategory),
+				Score:        match.Score,
+				Slug:         category.Slug,
+				CategorySlug: category.Slug,
+				ImageUrl:     category.CoverImageUrl,
+			})
+		}
+	}
+
+	if types[models.SearchTypeServiceStep] {
+		matches, err := findSearchMatches(ctx, configs.ServiceStepsColl, serviceStepSearchFields, bson.M{"category_id": bson.M{"$in": categoryIDs}}, q, terms)
+		if err != nil {
+			log.Printf("SearchHandler: Failed to search service steps: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to search",
+			})
+		}
+		for _, match := range matches {
+			var step models.ServiceStep
+			if err := decodeSearchDocument(match.Doc, &step); err != nil {
+				log.Printf("SearchHandler: Failed to decode service step: %v", err)
+				continue
+			}
+			raw := step
+			step = step.Localized(locale)
+			texts := []string{}
+			for _, steps := range []models.ServiceStep{step, raw} {
+				for _, section := range steps.Sections {
+					texts = append(texts, section.Text)
+					texts = append(texts, section.Headings...)
+				}
+			}
+			texts = append(texts, step.Title, raw.Title)
+			hits = append(hits, SearchHit{
+				Type:         models.SearchTypeServiceStep,
+				ID:           step.ID.Hex(),
+				Title:        step.Title,
+				Snippet:      searchSnippet(terms, texts...),
+				Score:        match.Score,
+				CategoryID:   step.CategoryID.Hex(),
+				CategorySlug: categoriesByID[step.CategoryID].Slug,
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+
+	total := len(hits)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	results := hits[start:end]
+
+	log.Printf("SearchHandler: Found %d hits for %q", total, q)
+	return c.JSON(fiber.Map{
+		"message": "Search completed successfully",
+		"data":    results,
+		"count":   len(results),
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
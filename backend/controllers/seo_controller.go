@@ -0,0 +1,404 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seoCacheControl lets crawlers and the CDN keep sitemap, feeds and link cards for a while;
+// new projects show up within the quarter hour.
+const seoCacheControl = "public, max-age=900"
+
+// siteURL is the address of the user site that sitemap, feed and link card URLs point to.
+// Without SITE_URL the API's own address is used, which only suits local development.
+func siteURL(c *fiber.Ctx) string {
+	if site := os.Getenv("SITE_URL"); site != "" {
+		return strings.TrimRight(site, "/")
+	}
+	return c.BaseURL()
+}
+
+// isCategoryPublic reports whether the category and all its ancestors are visible.
+func isCategoryPublic(ctx context.Context, category *models.Category) (bool, error) {
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, visible := range models.VisibleCategories(categories) {
+		if visible.ID == category.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cardImage picks the preview image of a project: the large variant of its image, else
+// the thumbnail of its YouTube video, else the cover of its category.
+func cardImage(ctx context.Context, project *models.Project, category *models.Category) string {
+	_, media := projectMedia(ctx, project)
+	thumbnail := ""
+	for _, item := range media {
+		if item.Kind == "image" {
+			if large, ok := item.Variants["large"]; ok {
+				return large
+			}
+			return item.Url
+		}
+		if item.ThumbnailUrl != "" && thumbnail == "" {
+			thumbnail = item.ThumbnailUrl
+		}
+	}
+	if thumbnail != "" {
+		return thumbnail
+	}
+	return category.CoverImageUrl
+}
+
+func GetSitemapHandler(c *fiber.Ctx) error {
+	log.Printf("GetSitemapHandler: Request to build the sitemap")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		log.Printf("GetSitemapHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build sitemap",
+		})
+	}
+	categories = models.VisibleCategories(categories)
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].DisplayOrder < categories[j].DisplayOrder
+	})
+	categoryIDs := make([]primitive.ObjectID, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	// Only the fields the sitemap needs, in the order the category pages show them
+	opts := options.Find().
+		SetProjection(bson.M{"slug": 1, "category_id": 1, "createdAt": 1, "updatedAt": 1}).
+		SetSort(bson.D{{Key: "pinned", Value: -1}, {Key: "position", Value: 1}})
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"category_id": bson.M{"$in": categoryIDs}, "published": true}, opts)
+	if err != nil {
+		log.Printf("GetSitemapHandler: Failed to fetch projects: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build sitemap",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("GetSitemapHandler: Failed to decode projects: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build sitemap",
+		})
+	}
+	projectsByCategory := map[primitive.ObjectID][]models.Project{}
+	for _, project := range projects {
+		projectsByCategory[project.CategoryID] = append(projectsByCategory[project.CategoryID], project)
+	}
+
+	site := siteURL(c)
+	urls := make([]models.SitemapURL, 0, len(models.StaticSitePaths)+len(categories)+len(projects))
+	for _, path := range models.StaticSitePaths {
+		urls = append(urls, models.SitemapURL{Loc: site + path})
+	}
+	for _, category := range categories {
+		// A category page changes whenever one of its projects does
+		var lastMod time.Time
+		for _, project := range projectsByCategory[category.ID] {
+			if modified := project.LastModified(); modified.After(lastMod) {
+				lastMod = modified
+			}
+		}
+		urls = append(urls, models.SitemapURL{
+			Loc:     site + models.CategoryPagePath(category.Slug),
+			LastMod: models.SitemapDate(lastMod),
+		})
+		for _, project := range projectsByCategory[category.ID] {
+			urls = append(urls, models.SitemapURL{
+				Loc:     site + models.ProjectPagePath(category.Slug, project),
+				LastMod: models.SitemapDate(project.LastModified()),
+			})
+		}
+	}
+
+	body, err := models.BuildSitemap(urls)
+	if err != nil {
+		log.Printf("GetSitemapHandler: Failed to render sitemap: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build sitemap",
+		})
+	}
+
+	log.Printf("GetSitemapHandler: Built sitemap with %d URLs", len(urls))
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, seoCacheControl)
+	return c.Send(body)
+}
+
+func GetCategoryFeedHandler(c *fiber.Ctx) error {
+	categorySlug := c.Params("category")
+	log.Printf("GetCategoryFeedHandler: Request to fetch the feed of category %s", categorySlug)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var category models.Category
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("GetCategoryFeedHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	if moved {
+		log.Printf("GetCategoryFeedHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
+
+	public, err := isCategoryPublic(ctx, &category)
+	if err != nil {
+		log.Printf("GetCategoryFeedHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch feed",
+		})
+	}
+	if !public {
+		log.Printf("GetCategoryFeedHandler: Category %s is hidden", categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(models.FeedEntryLimit)
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{"category_id": category.ID, "published": true}, opts)
+	if err != nil {
+		log.Printf("GetCategoryFeedHandler: Failed to fetch projects of category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch feed",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		log.Printf("GetCategoryFeedHandler: Failed to decode projects of category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch feed",
+		})
+	}
+
+	locale := requestLocale(c)
+	localized := category.Localized(locale)
+	site := siteURL(c)
+
+	var updated time.Time
+	entries := make([]models.AtomEntry, 0, len(projects))
+	for _, project := range projects {
+		modified := project.LastModified()
+		if modified.After(updated) {
+			updated = modified
+		}
+		links := []models.AtomLink{{Href: site + models.ProjectPagePath(category.Slug, project), Rel: "alternate", Type: "text/html"}}
+		if project.ImageUrl != "" {
+			links = append(links, models.AtomLink{Href: models.AbsoluteURL(site, project.ImageUrl), Rel: "enclosure"})
+		}
+		project = project.Localized(locale)
+		entries = append(entries, models.AtomEntry{
+			ID:        models.AtomID(site, "project:"+project.ID.Hex()),
+			Title:     project.Title,
+			Published: models.AtomDate(project.CreatedAt),
+			Updated:   models.AtomDate(modified),
+			Links:     links,
+			Summary:   models.NormalizeMultiline(project.Description),
+		})
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	body, err := models.BuildAtomFeed(models.AtomFeed{
+		Lang:    locale,
+		ID:      models.AtomID(site, "category:"+category.ID.Hex()),
+		Title:   localized.NameCategory + " | " + models.SiteName,
+		Updated: models.AtomDate(updated),
+		Author:  models.AtomPerson{Name: models.SiteName},
+		Links: []models.AtomLink{
+			{Href: c.BaseURL() + c.OriginalURL(), Rel: "self", Type: "application/atom+xml"},
+			{Href: site + models.CategoryPagePath(category.Slug), Rel: "alternate", Type: "text/html"},
+		},
+		Entries: entries,
+	})
+	if err != nil {
+		log.Printf("GetCategoryFeedHandler: Failed to render feed of category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch feed",
+		})
+	}
+
+	log.Printf("GetCategoryFeedHandler: Built feed of category %s with %d entries", categorySlug, len(entries))
+	c.Set(fiber.HeaderContentType, "application/atom+xml; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, seoCacheControl)
+	return c.Send(body)
+}
+
+func GetCategoryCardHandler(c *fiber.Ctx) error {
+	categorySlug := c.Params("category")
+	log.Printf("GetCategoryCardHandler: Request to fetch the link card of category %s", categorySlug)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category models.Category
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("GetCategoryCardHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	if moved {
+		log.Printf("GetCategoryCardHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
+
+	public, err := isCategoryPublic(ctx, &category)
+	if err != nil {
+		log.Printf("GetCategoryCardHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch link card",
+		})
+	}
+	if !public {
+		log.Printf("GetCategoryCardHandler: Category %s is hidden", categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	locale := requestLocale(c)
+	category = category.Localized(locale)
+	site := siteURL(c)
+
+	title := category.SeoTitle
+	if title == "" {
+		title = category.NameCategory
+	}
+	description := category.SeoDescription
+	if description == "" {
+		description = category.Description
+	}
+	image := ""
+	if category.CoverImageUrl != "" {
+		image = models.AbsoluteURL(site, category.CoverImageUrl)
+	}
+	card := models.NewLinkCard("website", title, description, site+models.CategoryPagePath(category.Slug), image, locale)
+
+	log.Printf("GetCategoryCardHandler: Retrieved link card of category %s", categorySlug)
+	c.Set(fiber.HeaderCacheControl, seoCacheControl)
+	return c.JSON(fiber.Map{
+		"message": "Link card retrieved successfully",
+		"data":    card,
+	})
+}
+
+func GetProjectCardHandler(c *fiber.Ctx) error {
+	categorySlug := c.Params("category")
+	idOrSlug := c.Params("id")
+	log.Printf("GetProjectCardHandler: Request to fetch the link card of project %s in category %s", idOrSlug, categorySlug)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category models.Category
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("GetProjectCardHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+	if moved {
+		log.Printf("GetProjectCardHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
+
+	public, err := isCategoryPublic(ctx, &category)
+	if err != nil {
+		log.Printf("GetProjectCardHandler: Failed to fetch categories: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch link card",
+		})
+	}
+	if !public {
+		log.Printf("GetProjectCardHandler: Category %s is hidden", categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	var project models.Project
+	err = configs.ProjectsColl.FindOne(ctx, projectLookupFilter(category.ID, idOrSlug)).Decode(&project)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("GetProjectCardHandler: Failed to fetch project %s: %v", idOrSlug, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch link card",
+			})
+		}
+		log.Printf("GetProjectCardHandler: Project %s not found in category %s", idOrSlug, categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found",
+		})
+	}
+	if !project.Published {
+		log.Printf("GetProjectCardHandler: Project %s is unpublished", idOrSlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found",
+		})
+	}
+
+	locale := requestLocale(c)
+	project = project.Localized(locale)
+	category = category.Localized(locale)
+	site := siteURL(c)
+
+	// Projects without a description of their own are described by their service
+	description := project.Description
+	if description == "" {
+		description = category.SeoDescription
+	}
+	if description == "" {
+		description = category.Description
+	}
+	image := cardImage(ctx, &project, &category)
+	if image != "" {
+		image = models.AbsoluteURL(site, image)
+	}
+	card := models.NewLinkCard("article", project.Title, description, site+models.ProjectPagePath(category.Slug, project), image, locale)
+
+	log.Printf("GetProjectCardHandler: Retrieved link card of project %s in category %s", project.ID.Hex(), categorySlug)
+	c.Set(fiber.HeaderCacheControl, seoCacheControl)
+	return c.JSON(fiber.Map{
+		"message": "Link card retrieved successfully",
+		"data":    card,
+	})
+}
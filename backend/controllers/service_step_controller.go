@@ -5,20 +5,40 @@ import (
 	"backend/models"
 	"context"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// lockServiceSteps marks the category's steps as changing inside a transaction. Two
+// transactions renumbering the same category then write the same category document,
+// so one of them hits a write conflict and WithTransaction retries it against the
+// other's result instead of both handing out the same step number.
+func lockServiceSteps(ctx mongo.SessionContext, categoryID primitive.ObjectID) error {
+	_, err := configs.CategoriesColl.UpdateOne(ctx, bson.M{"_id": categoryID}, bson.M{"$currentDate": bson.M{"stepsUpdatedAt": true}})
+	return err
+}
+
 type ServiceStepRequest struct {
-	Categories string   `json:"categories"`
-	Title      string   `json:"title"`
-	Subtitles  []string `json:"subtitles"`
-	Headings   []string `json:"headings"`
+	Categories string            `json:"categories"`
+	Title      string            `json:"title"`
+	Sections   []models.Subtitle `json:"sections"`
+	Subtitles  []string          `json:"subtitles"`
+	Headings   []string          `json:"headings"`
+	Version    *int64            `json:"version,omitempty"`
+}
+
+// sections returns the request content as sections, accepting the legacy flat fields when no sections are sent.
+func (r *ServiceStepRequest) sections() []models.Subtitle {
+	if len(r.Sections) > 0 {
+		return models.NormalizeSections(r.Sections)
+	}
+	return models.SectionsFromFlat(r.Subtitles, r.Headings)
 }
 
 func AddServiceStepHandler(c *fiber.Ctx) error {
@@ -38,15 +58,15 @@ func AddServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("AddServiceStepHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("AddServiceStepHandler: User %s requested to add service step in category %s", userEmail, categoryName)
+	log.Printf("AddServiceStepHandler: User %s requested to add service step in category %s", userEmail, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -71,10 +91,11 @@ func AddServiceStepHandler(c *fiber.Ctx) error {
 			"error": "Service step title is required",
 		})
 	}
-	if len(req.Subtitles) == 0 && len(req.Headings) == 0 {
-		log.Printf("AddServiceStepHandler: No subtitle or heading provided")
+	sections := req.sections()
+	if len(sections) == 0 {
+		log.Printf("AddServiceStepHandler: No section, subtitle or heading provided")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "At least one subtitle or heading is required",
+			"error": "At least one section, subtitle or heading is required",
 		})
 	}
 
@@ -95,36 +116,48 @@ func AddServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	if category.NameCategory != categoryName {
-		log.Printf("AddServiceStepHandler: Category ID %s does not match name %s", categoryObjID, categoryName)
+	var urlCategory models.Category
+	if _, err := findCategoryBySlug(ctx, categorySlug, &urlCategory); err != nil || urlCategory.ID != category.ID {
+		log.Printf("AddServiceStepHandler: Category ID %s does not match category %s", categoryObjID, categorySlug)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Category ID does not match category name in URL",
+			"error": "Category ID does not match category in URL",
 		})
 	}
 
-	// Filter out empty subtitles and headings
-	filteredSubtitles := []string{}
-	for _, subtitle := range req.Subtitles {
-		if subtitle != "" {
-			filteredSubtitles = append(filteredSubtitles, subtitle)
-		}
-	}
-	filteredHeadings := []string{}
-	for _, heading := range req.Headings {
-		if heading != "" {
-			filteredHeadings = append(filteredHeadings, heading)
-		}
-	}
-
 	serviceStep := models.ServiceStep{
 		CategoryID: categoryObjID,
 		Title:      req.Title,
-		Subtitles:  filteredSubtitles,
-		Headings:   filteredHeadings,
+		Sections:   sections,
 		CreatedAt:  time.Now(),
+		Version:    1,
 	}
 
-	result, err := configs.ServiceStepsColl.InsertOne(ctx, serviceStep)
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("AddServiceStepHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	// Append the new step after the last one of the category
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		if err := lockServiceSteps(sessionContext, categoryObjID); err != nil {
+			return nil, err
+		}
+		stepNumber, err := nextSequence(sessionContext, configs.ServiceStepsColl, categoryObjID, "stepNumber", 1)
+		if err != nil {
+			return nil, err
+		}
+		serviceStep.StepNumber = stepNumber
+		result, err := configs.ServiceStepsColl.InsertOne(sessionContext, serviceStep)
+		if err != nil {
+			return nil, err
+		}
+		serviceStep.ID = result.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
 	if err != nil {
 		log.Printf("AddServiceStepHandler: Failed to add service step: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -132,8 +165,15 @@ func AddServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	serviceStep.ID = result.InsertedID.(primitive.ObjectID)
-	log.Printf("AddServiceStepHandler: Service step %s added successfully by user %s in category %s", serviceStep.ID.Hex(), userEmail, categoryName)
+	serviceStep.FlattenSections()
+	c.Set(fiber.HeaderETag, formatETag(serviceStep.Version))
+	if err := recordCreatedRevision(ctx, models.RevisionEntityServiceStep, serviceStep.ID, serviceStep, userEmail); err != nil {
+		log.Printf("AddServiceStepHandler: Failed to record revision for service step %s: %v", serviceStep.ID.Hex(), err)
+	}
+	recordAudit(c, userEmail, models.AuditActionCreate, models.AuditEntityServiceStep, serviceStep.ID, nil, auditSummaryOf(serviceStep), "")
+	publishEvent(models.EventServiceStepCreated, userEmail, serviceStep.ID, serviceStep.CategoryID, serviceStep)
+
+	log.Printf("AddServiceStepHandler: Service step %s added successfully by user %s in category %s", serviceStep.ID.Hex(), userEmail, categorySlug)
 	return c.JSON(fiber.Map{
 		"message": "Service step added successfully",
 		"data":    serviceStep,
@@ -165,15 +205,15 @@ func UpdateServiceStepsHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("UpdateServiceStepsHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("UpdateServiceStepsHandler: User %s requested to update service step ID %s in category %s", userEmail, stepID, categoryName)
+	log.Printf("UpdateServiceStepsHandler: User %s requested to update service step ID %s in category %s", userEmail, stepID, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -198,10 +238,11 @@ func UpdateServiceStepsHandler(c *fiber.Ctx) error {
 			"error": "Service step title is required",
 		})
 	}
-	if len(req.Subtitles) == 0 && len(req.Headings) == 0 {
-		log.Printf("UpdateServiceStepsHandler: No subtitle or heading provided")
+	sections := req.sections()
+	if len(sections) == 0 {
+		log.Printf("UpdateServiceStepsHandler: No section, subtitle or heading provided")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "At least one subtitle or heading is required",
+			"error": "At least one section, subtitle or heading is required",
 		})
 	}
 
@@ -222,10 +263,11 @@ func UpdateServiceStepsHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	if category.NameCategory != categoryName {
-		log.Printf("UpdateServiceStepsHandler: Category ID %s does not match name %s", categoryObjID, categoryName)
+	var urlCategory models.Category
+	if _, err := findCategoryBySlug(ctx, categorySlug, &urlCategory); err != nil || urlCategory.ID != category.ID {
+		log.Printf("UpdateServiceStepsHandler: Category ID %s does not match category %s", categoryObjID, categorySlug)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Category ID does not match category name in URL",
+			"error": "Category ID does not match category in URL",
 		})
 	}
 
@@ -237,63 +279,73 @@ func UpdateServiceStepsHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateServiceStepsHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
 	var existingStep models.ServiceStep
 	err = configs.ServiceStepsColl.FindOne(ctx, bson.M{"_id": stepObjID, "category_id": categoryObjID}).Decode(&existingStep)
 	if err != nil {
-		log.Printf("UpdateServiceStepsHandler: Service step ID %s not found in category %s: %v", stepID, categoryName, err)
+		log.Printf("UpdateServiceStepsHandler: Service step ID %s not found in category %s: %v", stepID, categorySlug, err)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Service step not found",
 		})
 	}
 
-	// Filter out empty subtitles and headings
-	filteredSubtitles := []string{}
-	for _, subtitle := range req.Subtitles {
-		if subtitle != "" {
-			filteredSubtitles = append(filteredSubtitles, subtitle)
-		}
-	}
-	filteredHeadings := []string{}
-	for _, heading := range req.Headings {
-		if heading != "" {
-			filteredHeadings = append(filteredHeadings, heading)
-		}
-	}
-
 	update := bson.M{
 		"$set": bson.M{
 			"title":       req.Title,
-			"subtitles":   filteredSubtitles,
-			"headings":    filteredHeadings,
+			"sections":    sections,
 			"category_id": categoryObjID,
 			"updatedAt":   time.Now(),
 		},
+		"$unset": bson.M{"subtitles": "", "headings": ""},
+		"$inc":   bson.M{"version": 1},
 	}
 
-	result, err := configs.ServiceStepsColl.UpdateOne(ctx, bson.M{"_id": stepObjID}, update)
-	if err != nil {
+	if err := ensureBaselineRevision(ctx, configs.ServiceStepsColl, models.RevisionEntityServiceStep, stepObjID); err != nil {
+		log.Printf("UpdateServiceStepsHandler: Failed to record baseline revision for service step %s: %v", stepID, err)
+	}
+	before := auditSnapshot(ctx, configs.ServiceStepsColl, stepObjID)
+	var updatedStep models.ServiceStep
+	snapshot, err := decodeWithSnapshot(configs.ServiceStepsColl.FindOneAndUpdate(ctx, withVersion(bson.M{"_id": stepObjID}, version, anyVersion), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)), &updatedStep)
+	if err != nil && err != mongo.ErrNoDocuments {
 		log.Printf("UpdateServiceStepsHandler: Failed to update service step ID %s: %v", stepID, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update service step",
 		})
 	}
 
-	if result.MatchedCount == 0 {
+	if err == mongo.ErrNoDocuments {
+		current, err := currentVersion(ctx, configs.ServiceStepsColl, bson.M{"_id": stepObjID})
+		if err == nil {
+			log.Printf("UpdateServiceStepsHandler: Stale write for service step ID %s, expected version %d, current %d", stepID, version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Service step was modified by someone else",
+				"version": current,
+			})
+		}
 		log.Printf("UpdateServiceStepsHandler: No service step matched for ID %s", stepID)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Service step not found",
 		})
 	}
 
-	var updatedStep models.ServiceStep
-	err = configs.ServiceStepsColl.FindOne(ctx, bson.M{"_id": stepObjID}).Decode(&updatedStep)
-	if err != nil {
-		log.Printf("UpdateServiceStepsHandler: Failed to fetch updated service step ID %s: %v", stepID, err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch updated service step",
-		})
+	if _, err := recordRevision(ctx, models.RevisionEntityServiceStep, stepObjID, snapshot, userEmail, nil); err != nil {
+		log.Printf("UpdateServiceStepsHandler: Failed to record revision for service step %s: %v", stepID, err)
 	}
 
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityServiceStep, stepObjID, before, auditSummaryOf(updatedStep), "")
+	publishEvent(models.EventServiceStepUpdated, userEmail, stepObjID, updatedStep.CategoryID, updatedStep)
+
+	updatedStep.FlattenSections()
+	c.Set(fiber.HeaderETag, formatETag(updatedStep.Version))
 	log.Printf("UpdateServiceStepsHandler: Service step %s updated successfully by user %s", stepID, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Service step updated successfully",
@@ -326,23 +378,23 @@ func DeleteServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("DeleteServiceStepHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("DeleteServiceStepHandler: User %s requested to delete service step ID %s in category %s", userEmail, stepID, categoryName)
+	log.Printf("DeleteServiceStepHandler: User %s requested to delete service step ID %s in category %s", userEmail, stepID, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("DeleteServiceStepHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("DeleteServiceStepHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
@@ -356,21 +408,52 @@ func DeleteServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := configs.ServiceStepsColl.DeleteOne(ctx, bson.M{"_id": stepObjID, "category_id": category.ID})
+	session, err := configs.Client.StartSession()
 	if err != nil {
-		log.Printf("DeleteServiceStepHandler: Failed to delete service step ID %s: %v", stepID, err)
+		log.Printf("DeleteServiceStepHandler: Failed to start session: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete service step",
+			"error": "Failed to start transaction",
 		})
 	}
+	defer session.EndSession(ctx)
 
-	if result.DeletedCount == 0 {
-		log.Printf("DeleteServiceStepHandler: No service step matched for ID %s in category %s", stepID, categoryName)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Service step not found",
+	// Delete the step and close the gap it leaves in the numbering
+	var step models.ServiceStep
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		err := configs.ServiceStepsColl.FindOne(sessionContext, bson.M{"_id": stepObjID, "category_id": category.ID}).Decode(&step)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := lockServiceSteps(sessionContext, category.ID); err != nil {
+			return nil, err
+		}
+		if _, err := configs.ServiceStepsColl.DeleteOne(sessionContext, bson.M{"_id": stepObjID}); err != nil {
+			return nil, err
+		}
+
+		_, err = configs.ServiceStepsColl.UpdateMany(sessionContext,
+			bson.M{"category_id": category.ID, "stepNumber": bson.M{"$gt": step.StepNumber}},
+			bson.M{"$inc": bson.M{"stepNumber": -1}},
+		)
+		return nil, err
+	})
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.Printf("DeleteServiceStepHandler: No service step matched for ID %s in category %s", stepID, categorySlug)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Service step not found",
+			})
+		}
+		log.Printf("DeleteServiceStepHandler: Failed to delete service step ID %s: %v", stepID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete service step",
 		})
 	}
 
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityServiceStep, stepObjID, auditSummaryOf(step), nil, "")
+	publishEvent(models.EventServiceStepDeleted, userEmail, stepObjID, category.ID, fiber.Map{"_id": stepObjID})
 	log.Printf("DeleteServiceStepHandler: Service step %s deleted successfully by user %s", stepID, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Service step deleted successfully",
@@ -378,31 +461,52 @@ func DeleteServiceStepHandler(c *fiber.Ctx) error {
 }
 
 func GetAllServiceStepsHandler(c *fiber.Ctx) error {
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("GetAllServiceStepsHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("GetAllServiceStepsHandler: Fetching service steps in category %s", categoryName)
+	log.Printf("GetAllServiceStepsHandler: Fetching service steps in category %s", categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("GetAllServiceStepsHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("GetAllServiceStepsHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
 	}
+	if moved {
+		log.Printf("GetAllServiceStepsHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
 
-	cursor, err := configs.ServiceStepsColl.Find(ctx, bson.M{"category_id": category.ID})
+	// Anonymous callers only see categories whose whole ancestry is visible
+	if !isSignedIn(c) {
+		public, err := isCategoryPublic(ctx, &category)
+		if err != nil {
+			log.Printf("GetAllServiceStepsHandler: Failed to check visibility of category %s: %v", categorySlug, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch service steps",
+			})
+		}
+		if !public {
+			log.Printf("GetAllServiceStepsHandler: Category %s is hidden", categorySlug)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+	}
+
+	cursor, err := configs.ServiceStepsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(serviceStepSort))
 	if err != nil {
-		log.Printf("GetAllServiceStepsHandler: Failed to fetch service steps for category %s: %v", categoryName, err)
+		log.Printf("GetAllServiceStepsHandler: Failed to fetch service steps for category %s: %v", categorySlug, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch service steps",
 		})
@@ -411,13 +515,19 @@ func GetAllServiceStepsHandler(c *fiber.Ctx) error {
 
 	var serviceSteps []models.ServiceStep
 	if err := cursor.All(ctx, &serviceSteps); err != nil {
-		log.Printf("GetAllServiceStepsHandler: Failed to process service steps for category %s: %v", categoryName, err)
+		log.Printf("GetAllServiceStepsHandler: Failed to process service steps for category %s: %v", categorySlug, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to process service steps",
 		})
 	}
 
-	log.Printf("GetAllServiceStepsHandler: Retrieved %d service steps for category %s", len(serviceSteps), categoryName)
+	locale := requestLocale(c)
+	for i := range serviceSteps {
+		serviceSteps[i] = serviceSteps[i].Localized(locale)
+		serviceSteps[i].FlattenSections()
+	}
+
+	log.Printf("GetAllServiceStepsHandler: Retrieved %d service steps for category %s", len(serviceSteps), categorySlug)
 	return c.JSON(fiber.Map{
 		"message": "Service steps retrieved successfully",
 		"data":    serviceSteps,
@@ -449,27 +559,31 @@ func GetServiceStepHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	categoryName := strings.Title(strings.ToLower(c.Params("category")))
-	if categoryName == "" {
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
 		log.Printf("GetServiceStepHandler: Category is missing")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category is required",
 		})
 	}
 
-	log.Printf("GetServiceStepHandler: User %s requested to fetch service step ID %s in category %s", userEmail, stepID, categoryName)
+	log.Printf("GetServiceStepHandler: User %s requested to fetch service step ID %s in category %s", userEmail, stepID, categorySlug)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var category models.Category
-	err := configs.CategoriesColl.FindOne(ctx, bson.M{"nameCategory": categoryName}).Decode(&category)
+	moved, err := findCategoryBySlug(ctx, categorySlug, &category)
 	if err != nil {
-		log.Printf("GetServiceStepHandler: Category %s not found: %v", categoryName, err)
+		log.Printf("GetServiceStepHandler: Category %s not found: %v", categorySlug, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Category not found",
 		})
 	}
+	if moved {
+		log.Printf("GetServiceStepHandler: Category slug %s was renamed to %s, redirecting", categorySlug, category.Slug)
+		return redirectToCategorySlug(c, &category)
+	}
 
 	stepObjID, err := primitive.ObjectIDFromHex(stepID)
 	if err != nil {
@@ -482,12 +596,14 @@ func GetServiceStepHandler(c *fiber.Ctx) error {
 	var serviceStep models.ServiceStep
 	err = configs.ServiceStepsColl.FindOne(ctx, bson.M{"_id": stepObjID, "category_id": category.ID}).Decode(&serviceStep)
 	if err != nil {
-		log.Printf("GetServiceStepHandler: Service step ID %s not found in category %s: %v", stepID, categoryName, err)
+		log.Printf("GetServiceStepHandler: Service step ID %s not found in category %s: %v", stepID, categorySlug, err)
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Service step not found",
 		})
 	}
 
+	serviceStep.FlattenSections()
+	c.Set(fiber.HeaderETag, formatETag(serviceStep.Version))
 	log.Printf("GetServiceStepHandler: Service step %s retrieved successfully by user %s", stepID, userEmail)
 	return c.JSON(fiber.Map{
 		"message": "Service step retrieved successfully",
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// serviceStepSort lists steps by their number; steps created before numbering existed keep creation order.
+var serviceStepSort = bson.D{
+	{Key: "stepNumber", Value: 1},
+	{Key: "createdAt", Value: 1},
+}
+
+type ServiceStepOrderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func ReorderServiceStepsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("ReorderServiceStepsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("ReorderServiceStepsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	categorySlug := c.Params("category")
+	if categorySlug == "" {
+		log.Printf("ReorderServiceStepsHandler: Category is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category is required",
+		})
+	}
+
+	log.Printf("ReorderServiceStepsHandler: User %s requested to reorder service steps in category %s", userEmail, categorySlug)
+
+	var req ServiceStepOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ReorderServiceStepsHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(req.IDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, raw := range req.IDs {
+		objID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			log.Printf("ReorderServiceStepsHandler: Invalid step ID %s: %v", raw, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid step ID %s", raw),
+			})
+		}
+		if seen[objID] {
+			log.Printf("ReorderServiceStepsHandler: Duplicate step ID %s", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Duplicate step ID %s", raw),
+			})
+		}
+		seen[objID] = true
+		ids = append(ids, objID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var category models.Category
+	_, err := findCategoryBySlug(ctx, categorySlug, &category)
+	if err != nil {
+		log.Printf("ReorderServiceStepsHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("ReorderServiceStepsHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	errIncompleteOrder := fmt.Errorf("order must list every service step of the category exactly once")
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		if err := lockServiceSteps(sessionContext, category.ID); err != nil {
+			return nil, err
+		}
+		count, err := configs.ServiceStepsColl.CountDocuments(sessionContext, bson.M{"category_id": category.ID})
+		if err != nil {
+			return nil, err
+		}
+		matched, err := configs.ServiceStepsColl.CountDocuments(sessionContext, bson.M{"category_id": category.ID, "_id": bson.M{"$in": ids}})
+		if err != nil {
+			return nil, err
+		}
+		if int(count) != len(ids) || int(matched) != len(ids) {
+			return nil, errIncompleteOrder
+		}
+
+		for i, id := range ids {
+			// Only steps that actually move get a new version
+			_, err := configs.ServiceStepsColl.UpdateOne(sessionContext,
+				bson.M{"_id": id, "category_id": category.ID, "stepNumber": bson.M{"$ne": i + 1}},
+				bson.M{"$set": bson.M{"stepNumber": i + 1}, "$inc": bson.M{"version": 1}},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+
+	if err != nil {
+		if err == errIncompleteOrder {
+			log.Printf("ReorderServiceStepsHandler: Incomplete order for category %s", categorySlug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Order must list every service step of the category exactly once",
+			})
+		}
+		log.Printf("ReorderServiceStepsHandler: Transaction failed for category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reorder service steps",
+		})
+	}
+
+	cursor, err := configs.ServiceStepsColl.Find(ctx, bson.M{"category_id": category.ID}, options.Find().SetSort(serviceStepSort))
+	if err != nil {
+		log.Printf("ReorderServiceStepsHandler: Failed to fetch service steps for category %s: %v", categorySlug, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch service steps",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	var serviceSteps []models.ServiceStep
+	if err := cursor.All(ctx, &serviceSteps); err != nil {
+		log.Printf("ReorderServiceStepsHandler: Failed to process service steps: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process service steps",
+		})
+	}
+
+	for i := range serviceSteps {
+		serviceSteps[i].FlattenSections()
+	}
+
+	recordAudit(c, userEmail, models.AuditActionReorder, models.AuditEntityCategory, category.ID, nil, bson.M{"serviceSteps": ids}, fmt.Sprintf("Reordered %d service steps", len(ids)))
+	publishEvent(models.EventCategoryUpdated, userEmail, category.ID, category.ID, fiber.Map{"_id": category.ID, "serviceSteps": ids})
+	log.Printf("ReorderServiceStepsHandler: Reordered %d service steps in category %s by user %s", len(ids), categorySlug, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Service steps reordered successfully",
+		"data":    serviceSteps,
+	})
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SiteSettingsRequest replaces the whole settings document; lists left out are emptied.
+type SiteSettingsRequest struct {
+	Contacts      []models.ContactChannel `json:"contacts"`
+	Social        []models.SocialLink     `json:"social"`
+	BusinessHours []models.BusinessHours  `json:"businessHours"`
+	HoursNote     string                  `json:"hoursNote"`
+	HeroSlides    []models.HeroSlide      `json:"heroSlides"`
+	FooterText    string                  `json:"footerText"`
+	Seo           models.SeoDefaults      `json:"seo"`
+	Version       *int64                  `json:"version,omitempty"`
+}
+
+// loadSiteSettings returns the saved settings, or the defaults while none have been saved.
+func loadSiteSettings(ctx context.Context) (models.SiteSettings, bool, error) {
+	var settings models.SiteSettings
+	err := configs.SiteSettingsColl.FindOne(ctx, bson.M{"key": models.SiteSettingsKey}).Decode(&settings)
+	if err == mongo.ErrNoDocuments {
+		return models.DefaultSiteSettings(), false, nil
+	}
+	if err != nil {
+		return models.SiteSettings{}, false, err
+	}
+	return settings, true, nil
+}
+
+func GetSiteSettingsHandler(c *fiber.Ctx) error {
+	log.Printf("GetSiteSettingsHandler: Request to fetch site settings")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	settings, saved, err := loadSiteSettings(ctx)
+	if err != nil {
+		log.Printf("GetSiteSettingsHandler: Failed to fetch site settings: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch site settings",
+		})
+	}
+
+	if !saved {
+		log.Printf("GetSiteSettingsHandler: Site settings not saved yet, serving defaults")
+	}
+	c.Set(fiber.HeaderETag, formatETag(settings.Version))
+	// Every page reads the settings; a short cache keeps edits showing up quickly
+	c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+	return c.JSON(fiber.Map{
+		"message": "Site settings retrieved successfully",
+		"data":    settings,
+	})
+}
+
+func UpdateSiteSettingsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateSiteSettingsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateSiteSettingsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	var req SiteSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateSiteSettingsHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateSiteSettingsHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	settings := models.SiteSettings{
+		Contacts:      req.Contacts,
+		Social:        req.Social,
+		BusinessHours: req.BusinessHours,
+		HoursNote:     req.HoursNote,
+		HeroSlides:    req.HeroSlides,
+		FooterText:    req.FooterText,
+		Seo:           req.Seo,
+	}
+	settings.Normalize()
+	if err := settings.Validate(); err != nil {
+		log.Printf("UpdateSiteSettingsHandler: Invalid site settings: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf("UpdateSiteSettingsHandler: User %s requested to update site settings", userEmail)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	existing, saved, err := loadSiteSettings(ctx)
+	if err != nil {
+		log.Printf("UpdateSiteSettingsHandler: Failed to fetch site settings: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update site settings",
+		})
+	}
+	// The defaults count as version 0 until the first save creates the document
+	if !saved && !anyVersion && version != 0 {
+		log.Printf("UpdateSiteSettingsHandler: Stale write, expected version %d, settings not saved yet", version)
+		c.Set(fiber.HeaderETag, formatETag(0))
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error":   "Site settings were modified by someone else",
+			"version": 0,
+		})
+	}
+
+	var before bson.M
+	if saved {
+		before = auditSnapshot(ctx, configs.SiteSettingsColl, existing.ID)
+		if err := ensureBaselineRevision(ctx, configs.SiteSettingsColl, models.RevisionEntitySiteSettings, existing.ID); err != nil {
+			log.Printf("UpdateSiteSettingsHandler: Failed to record baseline revision: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update site settings",
+			})
+		}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"contacts":      settings.Contacts,
+			"social":        settings.Social,
+			"businessHours": settings.BusinessHours,
+			"hoursNote":     settings.HoursNote,
+			"heroSlides":    settings.HeroSlides,
+			"footerText":    settings.FooterText,
+			"seo":           settings.Seo,
+			"updatedAt":     time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	var updated models.SiteSettings
+	snapshot, err := decodeWithSnapshot(configs.SiteSettingsColl.FindOneAndUpdate(ctx,
+		withVersion(bson.M{"key": models.SiteSettingsKey}, version, anyVersion),
+		update,
+		options.FindOneAndUpdate().SetUpsert(!saved).SetReturnDocument(options.After),
+	), &updated)
+	if err == mongo.ErrNoDocuments || mongo.IsDuplicateKeyError(err) {
+		// Another admin saved in between: a newer version, or the first save raced ours
+		current, verr := currentVersion(ctx, configs.SiteSettingsColl, bson.M{"key": models.SiteSettingsKey})
+		if verr != nil {
+			log.Printf("UpdateSiteSettingsHandler: Failed to fetch current version: %v", verr)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update site settings",
+			})
+		}
+		log.Printf("UpdateSiteSettingsHandler: Stale write, expected version %d, current %d", version, current)
+		c.Set(fiber.HeaderETag, formatETag(current))
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error":   "Site settings were modified by someone else",
+			"version": current,
+		})
+	}
+	if err != nil {
+		log.Printf("UpdateSiteSettingsHandler: Failed to update site settings: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update site settings",
+		})
+	}
+
+	if _, err := recordRevision(ctx, models.RevisionEntitySiteSettings, updated.ID, snapshot, userEmail, nil); err != nil {
+		log.Printf("UpdateSiteSettingsHandler: Failed to record revision for site settings: %v", err)
+	}
+
+	action := models.AuditActionUpdate
+	if !saved {
+		action = models.AuditActionCreate
+	}
+	recordAudit(c, userEmail, action, models.AuditEntitySiteSettings, updated.ID, before, auditSummaryOf(updated), "")
+	publishEvent(models.EventSettingsUpdated, userEmail, updated.ID, primitive.NilObjectID, updated)
+
+	log.Printf("UpdateSiteSettingsHandler: Site settings updated to version %d by user %s", updated.Version, userEmail)
+	c.Set(fiber.HeaderETag, formatETag(updated.Version))
+	return c.JSON(fiber.Map{
+		"message": "Site settings updated successfully",
+		"data":    updated,
+	})
+}
+
+func GetSiteSettingsRevisionsHandler(c *fiber.Ctx) error {
+	return listRevisions(c, "GetSiteSettingsRevisionsHandler", models.RevisionEntitySiteSettings)
+}
+
+func DiffSiteSettingsRevisionsHandler(c *fiber.Ctx) error {
+	return diffRevisions(c, "DiffSiteSettingsRevisionsHandler", models.RevisionEntitySiteSettings)
+}
+
+func RestoreSiteSettingsRevisionHandler(c *fiber.Ctx) error {
+	return restoreRevision(c, "RestoreSiteSettingsRevisionHandler", models.RevisionEntitySiteSettings)
+}
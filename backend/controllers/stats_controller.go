@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// statsTimezone is where the studio works; weeks and months are counted in local time.
+const statsTimezone = "Asia/Bangkok"
+
+const (
+	statsCacheTTL       = time.Minute
+	maxStatsWeeks       = 104
+	maxStatsMonths      = 60
+	statsRecentActivity = 10
+	defaultStatsWeeks   = 12
+	defaultStatsMonths  = 12
+)
+
+type CategoryCount struct {
+	CategoryID   primitive.ObjectID `json:"categoryId" bson:"_id"`
+	Slug         string             `json:"slug"`
+	NameCategory string             `json:"nameCategory"`
+	Count        int                `json:"count" bson:"count"`
+}
+
+type StorageUsage struct {
+	Type  string `json:"type" bson:"_id"`
+	Files int    `json:"files" bson:"files"`
+	Bytes int64  `json:"bytes" bson:"bytes"`
+}
+
+// ActivityEntry is one recent administrative change, taken from the audit log.
+type ActivityEntry struct {
+	Action      string              `json:"action"`
+	EntityType  string              `json:"entityType"`
+	EntityID    *primitive.ObjectID `json:"entityId,omitempty"`
+	Title       string              `json:"title,omitempty"`
+	Detail      string              `json:"detail,omitempty"`
+	AuthorEmail string              `json:"authorEmail"`
+	At          time.Time           `json:"at"`
+}
+
+type StatsTotals struct {
+	Projects          int64 `json:"projects"`
+	PublishedProjects int64 `json:"publishedProjects"`
+	Categories        int64 `json:"categories"`
+	ServiceSteps      int64 `json:"serviceSteps"`
+	Contacts          int64 `json:"contacts"`
+	NewContacts       int64 `json:"newContacts"`
+	OpenQuoteRequests int64 `json:"openQuoteRequests"`
+}
+
+type DashboardStats struct {
+	Totals                  StatsTotals          `json:"totals"`
+	ProjectsPerCategory     []CategoryCount      `json:"projectsPerCategory"`
+	ServiceStepsPerCategory []CategoryCount      `json:"serviceStepsPerCategory"`
+	ProjectsPerWeek         []models.PeriodCount `json:"projectsPerWeek"`
+	ProjectsPerMonth        []models.PeriodCount `json:"projectsPerMonth"`
+	Storage                 []StorageUsage       `json:"storage"`
+	RecentActivity          []ActivityEntry      `json:"recentActivity"`
+	GeneratedAt             time.Time            `json:"generatedAt"`
+}
+
+// statsCache keeps computed stats per query for statsCacheTTL, so dashboards polling the
+// endpoint do not rerun every aggregation.
+var statsCache = struct {
+	sync.Mutex
+	entries map[string]*DashboardStats
+}{entries: map[string]*DashboardStats{}}
+
+func GetDashboardStatsHandler(c *fiber.Ctx) error {
+	weeks := c.QueryInt("weeks", defaultStatsWeeks)
+	if weeks < 1 || weeks > maxStatsWeeks {
+		weeks = defaultStatsWeeks
+	}
+	months := c.QueryInt("months", defaultStatsMonths)
+	if months < 1 || months > maxStatsMonths {
+		months = defaultStatsMonths
+	}
+
+	key := fmt.Sprintf("%d/%d", weeks, months)
+	statsCache.Lock()
+	cached := statsCache.entries[key]
+	statsCache.Unlock()
+	if cached != nil && time.Since(cached.GeneratedAt) < statsCacheTTL && !c.QueryBool("refresh") {
+		return c.JSON(fiber.Map{
+			"message": "Stats fetched successfully",
+			"data":    cached,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	stats, err := computeDashboardStats(ctx, weeks, months)
+	if err != nil {
+		log.Printf("GetDashboardStatsHandler: Failed to compute stats: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to compute stats",
+		})
+	}
+
+	statsCache.Lock()
+	statsCache.entries[key] = stats
+	statsCache.Unlock()
+
+	return c.JSON(fiber.Map{
+		"message": "Stats fetched successfully",
+		"data":    stats,
+	})
+}
+
+// studioLocation loads statsTimezone, falling back to UTC when the tz database is missing.
+func studioLocation() *time.Location {
+	loc, err := time.LoadLocation(statsTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func computeDashboardStats(ctx context.Context, weeks, months int) (*DashboardStats, error) {
+	loc := studioLocation()
+	now := time.Now().In(loc)
+	stats := &DashboardStats{GeneratedAt: time.Now()}
+	var err error
+
+	counts := []struct {
+		coll   *mongo.Collection
+		filter bson.M
+		target *int64
+	}{
+		{configs.ProjectsColl, bson.M{}, &stats.Totals.Projects},
+		{configs.ProjectsColl, bson.M{"published": true}, &stats.Totals.PublishedProjects},
+		{configs.CategoriesColl, bson.M{}, &stats.Totals.Categories},
+		{configs.ServiceStepsColl, bson.M{}, &stats.Totals.ServiceSteps},
+		{configs.ContactsColl, bson.M{}, &stats.Totals.Contacts},
+		{configs.ContactsColl, bson.M{"status": models.ContactStatusNew}, &stats.Totals.NewContacts},
+		{configs.QuotesColl, bson.M{"status": bson.M{"$in": bson.A{models.QuoteStatusNew, models.QuoteStatusReviewing, models.QuoteStatusQuoted}}}, &stats.Totals.OpenQuoteRequests},
+	}
+	for _, count := range counts {
+		if *count.target, err = count.coll.CountDocuments(ctx, count.filter); err != nil {
+			return nil, err
+		}
+	}
+
+	categories, err := loadAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stats.ProjectsPerCategory, err = countPerCategory(ctx, configs.ProjectsColl, categories); err != nil {
+		return nil, err
+	}
+	if stats.ServiceStepsPerCategory, err = countPerCategory(ctx, configs.ServiceStepsColl, categories); err != nil {
+		return nil, err
+	}
+
+	// Start the ranges at the beginning of the oldest week and month shown
+	weekStart := now.AddDate(0, 0, -7*(weeks-1)-int(now.Weekday()+6)%7)
+	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, loc)
+	perWeek, err := countProjectsPerPeriod(ctx, "%G-W%V", weekStart)
+	if err != nil {
+		return nil, err
+	}
+	stats.ProjectsPerWeek = models.FillPeriodCounts(models.WeekPeriods(now, weeks), perWeek)
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -(months - 1), 0)
+	perMonth, err := countProjectsPerPeriod(ctx, "%Y-%m", monthStart)
+	if err != nil {
+		return nil, err
+	}
+	stats.ProjectsPerMonth = models.FillPeriodCounts(models.MonthPeriods(now, months), perMonth)
+
+	filesColl := configs.Client.Database("ProjectsDB").Collection("FilesColl")
+	cursor, err := filesColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$type",
+			"files": bson.M{"$sum": 1},
+			"bytes": bson.M{"$sum": "$size"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"bytes": -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.Storage = []StorageUsage{}
+	if err := cursor.All(ctx, &stats.Storage); err != nil {
+		return nil, err
+	}
+
+	if stats.RecentActivity, err = recentActivity(ctx); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// countPerCategory counts documents by category_id, including categories with none.
+func countPerCategory(ctx context.Context, coll *mongo.Collection, categories []models.Category) ([]CategoryCount, error) {
+	cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$category_id", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var grouped []CategoryCount
+	if err := cursor.All(ctx, &grouped); err != nil {
+		return nil, err
+	}
+	byCategory := map[primitive.ObjectID]int{}
+	for _, group := range grouped {
+		byCategory[group.CategoryID] = group.Count
+	}
+
+	result := make([]CategoryCount, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, CategoryCount{
+			CategoryID:   category.ID,
+			Slug:         category.Slug,
+			NameCategory: category.NameCategory,
+			Count:        byCategory[category.ID],
+		})
+	}
+	return result, nil
+}
+
+// countProjectsPerPeriod groups projects created since start by the date format, which is
+// evaluated in statsTimezone.
+func countProjectsPerPeriod(ctx context.Context, format string, start time.Time) ([]models.PeriodCount, error) {
+	cursor, err := configs.ProjectsColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"createdAt": bson.M{"$gte": start}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   format,
+				"date":     "$createdAt",
+				"timezone": statsTimezone,
+			}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var counts []models.PeriodCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// recentActivity lists the latest changes from the audit log, so deletes, moves and
+// settings changes show up next to content edits. Sign-ins are left out.
+func recentActivity(ctx context.Context) ([]ActivityEntry, error) {
+	cursor, err := configs.AuditColl.Find(ctx,
+		bson.M{"action": bson.M{"$ne": models.AuditActionLogin}},
+		options.Find().
+			SetSort(bson.M{"createdAt": -1}).
+			SetLimit(statsRecentActivity).
+			SetProjection(bson.M{"ip": 0, "userAgent": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	activity := make([]ActivityEntry, 0, len(entries))
+	for _, entry := range entries {
+		activity = append(activity, ActivityEntry{
+			Action:      entry.Action,
+			EntityType:  entry.EntityType,
+			EntityID:    entry.EntityID,
+			Title:       activityTitle(entry),
+			Detail:      entry.Detail,
+			AuthorEmail: entry.Actor,
+			At:          entry.CreatedAt,
+		})
+	}
+	return activity, nil
+}
+
+// activityTitle names the changed entity from its audit summary, preferring the state after the change.
+func activityTitle(entry models.AuditEntry) string {
+	for _, summary := range []bson.M{entry.After, entry.Before} {
+		for _, field := range []string{"title", "nameCategory", "name", "email"} {
+			if title, ok := summary[field].(string); ok && title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
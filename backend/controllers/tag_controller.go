@@ -0,0 +1,621 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TagRequest struct {
+	Name    string  `json:"name"`
+	Slug    string  `json:"slug,omitempty"`
+	Group   *string `json:"group,omitempty"`
+	Version *int64  `json:"version,omitempty"`
+}
+
+type ProjectTagsRequest struct {
+	Tags    []string `json:"tags"`
+	Version *int64   `json:"version"`
+}
+
+var tagSort = bson.D{
+	{Key: "group", Value: 1},
+	{Key: "name", Value: 1},
+}
+
+// resolveTagSlugs normalizes tag references to slugs and checks that every tag exists.
+func resolveTagSlugs(ctx context.Context, refs []string) ([]string, error) {
+	slugs := models.ParseTagList(strings.Join(refs, ","))
+	if len(slugs) == 0 {
+		return []string{}, nil
+	}
+
+	cursor, err := configs.TagsColl.Find(ctx, bson.M{"slug": bson.M{"$in": slugs}}, options.Find().SetProjection(bson.M{"slug": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var found []models.Tag
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, tag := range found {
+		known[tag.Slug] = true
+	}
+	var missing []string
+	for _, slug := range slugs {
+		if !known[slug] {
+			missing = append(missing, slug)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Unknown tags: %s", strings.Join(missing, ", "))
+	}
+	return slugs, nil
+}
+
+// applyTagFilter adds the ?tags= filter to a project query. tagMode=and (the default)
+// requires every tag, tagMode=or any of them.
+func applyTagFilter(c *fiber.Ctx, filter bson.M) (status int, msg string) {
+	slugs := models.ParseTagList(c.Query("tags"))
+	if len(slugs) == 0 {
+		return 0, ""
+	}
+	switch strings.ToLower(c.Query("tagMode", "and")) {
+	case "and":
+		filter["tags"] = bson.M{"$all": slugs}
+	case "or":
+		filter["tags"] = bson.M{"$in": slugs}
+	default:
+		return fiber.StatusBadRequest, "tagMode must be and or or"
+	}
+	return 0, ""
+}
+
+// projectTagFacets counts the tags of the projects matching filter, most used first.
+func projectTagFacets(ctx context.Context, filter bson.M) ([]models.TagFacet, error) {
+	cursor, err := configs.ProjectsColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		Slug  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, 0, len(counts))
+	for _, count := range counts {
+		slugs = append(slugs, count.Slug)
+	}
+	cursor, err = configs.TagsColl.Find(ctx, bson.M{"slug": bson.M{"$in": slugs}})
+	if err != nil {
+		return nil, err
+	}
+	var tags []models.Tag
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+	tagsBySlug := map[string]models.Tag{}
+	for _, tag := range tags {
+		tagsBySlug[tag.Slug] = tag
+	}
+
+	facets := []models.TagFacet{}
+	for _, count := range counts {
+		tag, ok := tagsBySlug[count.Slug]
+		if !ok {
+			continue
+		}
+		facets = append(facets, models.TagFacet{Slug: tag.Slug, Name: tag.Name, Group: tag.Group, Count: count.Count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets, nil
+}
+
+func GetTagsHandler(c *fiber.Ctx) error {
+	log.Printf("GetTagsHandler: Request to fetch tags")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if group := c.Query("group"); group != "" {
+		filter["group"] = group
+	}
+
+	cursor, err := configs.TagsColl.Find(ctx, filter, options.Find().SetSort(tagSort))
+	if err != nil {
+		log.Printf("GetTagsHandler: Failed to fetch tags: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch tags",
+		})
+	}
+	defer cursor.Close(ctx)
+
+	tags := []models.Tag{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		log.Printf("GetTagsHandler: Failed to process tags: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process tags",
+		})
+	}
+
+	log.Printf("GetTagsHandler: Retrieved %d tags", len(tags))
+	return c.JSON(fiber.Map{
+		"message": "Tags retrieved successfully",
+		"data":    tags,
+		"count":   len(tags),
+	})
+}
+
+func AddTagHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("AddTagHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("AddTagHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	log.Printf("AddTagHandler: User %s requested to add tag", userEmail)
+
+	var req TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("AddTagHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	name := models.NormalizeText(req.Name)
+	if name == "" {
+		log.Printf("AddTagHandler: Name is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Name is required",
+		})
+	}
+
+	slug := models.Slugify(name)
+	if req.Slug != "" {
+		if !models.IsSlugValid(req.Slug) {
+			log.Printf("AddTagHandler: Invalid slug %s", req.Slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Slug must be lowercase letters, digits and single dashes",
+			})
+		}
+		slug = req.Slug
+	}
+	if slug == "" {
+		log.Printf("AddTagHandler: Cannot derive slug from name %s", name)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Slug is required when the name has no letters or digits",
+		})
+	}
+
+	tag := models.Tag{
+		Name:      name,
+		Slug:      slug,
+		CreatedAt: time.Now(),
+		Version:   1,
+	}
+	if req.Group != nil {
+		tag.Group = models.NormalizeText(*req.Group)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := configs.TagsColl.InsertOne(ctx, tag)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			log.Printf("AddTagHandler: Tag %s or slug %s already exists", name, slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Tag name or slug already exists",
+			})
+		}
+		log.Printf("AddTagHandler: Failed to create tag %s: %v", name, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create tag",
+		})
+	}
+
+	tag.ID = result.InsertedID.(primitive.ObjectID)
+	recordAudit(c, userEmail, models.AuditActionCreate, models.AuditEntityTag, tag.ID, nil, auditSummaryOf(tag), "")
+	publishEvent(models.EventTagCreated, userEmail, tag.ID, primitive.NilObjectID, tag)
+	c.Set(fiber.HeaderETag, formatETag(tag.Version))
+	log.Printf("AddTagHandler: Tag %s created successfully by user %s", slug, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Tag created successfully",
+		"data":    tag,
+	})
+}
+
+func UpdateTagHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateTagHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateTagHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	log.Printf("UpdateTagHandler: User %s requested to update tag ID %s", userEmail, id)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("UpdateTagHandler: Invalid tag ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid tag ID",
+		})
+	}
+
+	var req TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateTagHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	name := models.NormalizeText(req.Name)
+	if name == "" {
+		log.Printf("UpdateTagHandler: Name is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Name is required",
+		})
+	}
+	if req.Slug != "" && !models.IsSlugValid(req.Slug) {
+		log.Printf("UpdateTagHandler: Invalid slug %s", req.Slug)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Slug must be lowercase letters, digits and single dashes",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateTagHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var existing models.Tag
+	if err := configs.TagsColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&existing); err != nil {
+		log.Printf("UpdateTagHandler: Tag ID %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Tag not found",
+		})
+	}
+
+	set := bson.M{"name": name, "updatedAt": time.Now()}
+	if req.Group != nil {
+		set["group"] = models.NormalizeText(*req.Group)
+	}
+	slugChanged := req.Slug != "" && req.Slug != existing.Slug
+	if slugChanged {
+		set["slug"] = req.Slug
+	}
+
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("UpdateTagHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	errStale := fmt.Errorf("tag was modified")
+
+	// Projects reference tags by slug, so a slug change is applied to them in the same transaction
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		result, err := configs.TagsColl.UpdateOne(sessionContext,
+			withVersion(bson.M{"_id": objID}, version, anyVersion),
+			bson.M{"$set": set, "$inc": bson.M{"version": 1}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, errStale
+		}
+		// Rewritten projects get a new version so editors holding the old tag list get a 412
+		if slugChanged {
+			_, err := configs.ProjectsColl.UpdateMany(sessionContext,
+				bson.M{"tags": existing.Slug},
+				bson.M{"$set": bson.M{"tags.$": req.Slug}, "$inc": bson.M{"version": 1}},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if err == errStale {
+			current, err := currentVersion(ctx, configs.TagsColl, bson.M{"_id": objID})
+			if err == nil {
+				log.Printf("UpdateTagHandler: Stale write for tag ID %s, expected version %d, current %d", id, version, current)
+				c.Set(fiber.HeaderETag, formatETag(current))
+				return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+					"error":   "Tag was modified by someone else",
+					"version": current,
+				})
+			}
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Tag not found",
+			})
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			log.Printf("UpdateTagHandler: Tag name %s or slug %s already exists", name, req.Slug)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Tag name or slug already exists",
+			})
+		}
+		log.Printf("UpdateTagHandler: Transaction failed for tag ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update tag",
+		})
+	}
+
+	var updatedTag models.Tag
+	if err := configs.TagsColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&updatedTag); err != nil {
+		log.Printf("UpdateTagHandler: Failed to fetch updated tag ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch updated tag",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityTag, objID, auditSummaryOf(existing), auditSummaryOf(updatedTag), "")
+	publishEvent(models.EventTagUpdated, userEmail, objID, primitive.NilObjectID, updatedTag)
+
+	c.Set(fiber.HeaderETag, formatETag(updatedTag.Version))
+	log.Printf("UpdateTagHandler: Tag %s updated successfully by user %s", updatedTag.Slug, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Tag updated successfully",
+		"data":    updatedTag,
+	})
+}
+
+func DeleteTagHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("DeleteTagHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("DeleteTagHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	log.Printf("DeleteTagHandler: User %s requested to delete tag ID %s", userEmail, id)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("DeleteTagHandler: Invalid tag ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid tag ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	session, err := configs.Client.StartSession()
+	if err != nil {
+		log.Printf("DeleteTagHandler: Failed to start session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start transaction",
+		})
+	}
+	defer session.EndSession(ctx)
+
+	var untagged int64
+	var tag models.Tag
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		if err := configs.TagsColl.FindOne(sessionContext, bson.M{"_id": objID}).Decode(&tag); err != nil {
+			return nil, err
+		}
+
+		// Remove the tag from every project that carries it
+		result, err := configs.ProjectsColl.UpdateMany(sessionContext,
+			bson.M{"tags": tag.Slug},
+			bson.M{"$pull": bson.M{"tags": tag.Slug}, "$inc": bson.M{"version": 1}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		untagged = result.ModifiedCount
+
+		_, err = configs.TagsColl.DeleteOne(sessionContext, bson.M{"_id": objID})
+		return nil, err
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.Printf("DeleteTagHandler: Tag ID %s not found", id)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Tag not found",
+			})
+		}
+		log.Printf("DeleteTagHandler: Transaction failed for tag ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete tag",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityTag, objID, auditSummaryOf(tag), nil, fmt.Sprintf("Removed from %d projects", untagged))
+	publishEvent(models.EventTagDeleted, userEmail, objID, primitive.NilObjectID, fiber.Map{"_id": objID, "slug": tag.Slug})
+	log.Printf("DeleteTagHandler: Tag ID %s deleted and removed from %d projects by user %s", id, untagged, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Tag deleted successfully",
+		"count":   untagged,
+	})
+}
+
+func SetProjectTagsHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("SetProjectTagsHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("SetProjectTagsHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	categorySlug := c.Params("category")
+	log.Printf("SetProjectTagsHandler: User %s requested to set tags of project ID %s in category %s", userEmail, id, categorySlug)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("SetProjectTagsHandler: Invalid project ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	var req ProjectTagsRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("SetProjectTagsHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("SetProjectTagsHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category models.Category
+	if _, err := findCategoryBySlug(ctx, categorySlug, &category); err != nil {
+		log.Printf("SetProjectTagsHandler: Category %s not found: %v", categorySlug, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	tags, err := resolveTagSlugs(ctx, req.Tags)
+	if err != nil {
+		log.Printf("SetProjectTagsHandler: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	before := auditSnapshot(ctx, configs.ProjectsColl, objID)
+	if err := ensureBaselineRevision(ctx, configs.ProjectsColl, models.RevisionEntityProject, objID); err != nil {
+		log.Printf("SetProjectTagsHandler: Failed to record baseline revision for project %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update project",
+		})
+	}
+	var updated models.Project
+	snapshot, err := decodeWithSnapshot(configs.ProjectsColl.FindOneAndUpdate(ctx,
+		withVersion(bson.M{"_id": objID, "category_id": category.ID}, version, anyVersion),
+		bson.M{"$set": bson.M{"tags": tags, "updatedAt": time.Now()}, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	), &updated)
+	if err == mongo.ErrNoDocuments {
+		if current, err := currentVersion(ctx, configs.ProjectsColl, bson.M{"_id": objID, "category_id": category.ID}); err == nil {
+			log.Printf("SetProjectTagsHandler: Stale write for project ID %s, expected version %d, current %d", id, version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Project was modified by someone else",
+				"version": current,
+			})
+		}
+		log.Printf("SetProjectTagsHandler: No project matched for ID %s in category %s", id, categorySlug)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Project not found or category does not match",
+		})
+	}
+	if err != nil {
+		log.Printf("SetProjectTagsHandler: Failed to update project ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update project",
+		})
+	}
+
+	if _, err := recordRevision(ctx, models.RevisionEntityProject, objID, snapshot, userEmail, nil); err != nil {
+		log.Printf("SetProjectTagsHandler: Failed to record revision for project %s: %v", id, err)
+	}
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityProject, objID, before, auditSnapshot(ctx, configs.ProjectsColl, objID), "")
+	publishEvent(models.EventProjectUpdated, userEmail, objID, category.ID, fiber.Map{"_id": objID, "tags": tags})
+
+	c.Set(fiber.HeaderETag, formatETag(updated.Version))
+	log.Printf("SetProjectTagsHandler: Project %s tagged %v by user %s", id, tags, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Project tags updated successfully",
+		"data": fiber.Map{
+			"_id":     id,
+			"tags":    tags,
+			"version": updated.Version,
+		},
+	})
+}
@@ -0,0 +1,479 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryTranslationRequest struct {
+	NameCategory   string `json:"nameCategory"`
+	Description    string `json:"description"`
+	SeoTitle       string `json:"seoTitle"`
+	SeoDescription string `json:"seoDescription"`
+}
+
+type ProjectTranslationRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     *int64 `json:"version"`
+}
+
+type ServiceStepTranslationRequest struct {
+	Title     string            `json:"title"`
+	Sections  []models.Subtitle `json:"sections"`
+	Subtitles []string          `json:"subtitles"`
+	Headings  []string          `json:"headings"`
+	Version   *int64            `json:"version"`
+}
+
+// requestLocale picks the locale for a public response from ?lang= or Accept-Language
+// and advertises it so caches keep one copy per language.
+func requestLocale(c *fiber.Ctx) string {
+	locale := models.MatchLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+	c.Set(fiber.HeaderContentLanguage, locale)
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return locale
+}
+
+// translationLocale validates the :locale param of the translation endpoints. The
+// default locale is the base content and is edited through the regular endpoints.
+func translationLocale(c *fiber.Ctx) (string, string) {
+	locale := c.Params("locale")
+	if !models.IsLocaleSupported(locale) {
+		return "", fmt.Sprintf("Unsupported locale %s, supported locales are %v", locale, models.SupportedLocales)
+	}
+	if locale == models.DefaultLocale {
+		return "", fmt.Sprintf("Locale %s is the base content, update it through the regular endpoint", locale)
+	}
+	return locale, ""
+}
+
+func UpdateCategoryTranslationHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateCategoryTranslationHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateCategoryTranslationHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	locale, msg := translationLocale(c)
+	if msg != "" {
+		log.Printf("UpdateCategoryTranslationHandler: %s", msg)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("UpdateCategoryTranslationHandler: User %s requested to update %s translation of category ID %s", userEmail, locale, id)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("UpdateCategoryTranslationHandler: Invalid category ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	var req CategoryTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateCategoryTranslationHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	translation := models.CategoryTranslation{
+		NameCategory:   models.NormalizeText(req.NameCategory),
+		Description:    models.NormalizeMultiline(req.Description),
+		SeoTitle:       models.NormalizeText(req.SeoTitle),
+		SeoDescription: models.NormalizeText(req.SeoDescription),
+	}
+	if translation.NameCategory == "" {
+		log.Printf("UpdateCategoryTranslationHandler: NameCategory is missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "NameCategory is required",
+		})
+	}
+	// The same limits apply to translated landing page text
+	translated := models.Category{Description: translation.Description, SeoTitle: translation.SeoTitle, SeoDescription: translation.SeoDescription}
+	if err := translated.ValidateMetadata(); err != nil {
+		log.Printf("UpdateCategoryTranslationHandler: Invalid translation: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := configs.CategoriesColl.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{
+			"$set": bson.M{"translations." + locale: translation, "updatedAt": time.Now()},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		log.Printf("UpdateCategoryTranslationHandler: Failed to update category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update category translation",
+		})
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("UpdateCategoryTranslationHandler: No category matched for ID %s", id)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	var updatedCategory models.Category
+	if err := configs.CategoriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&updatedCategory); err != nil {
+		log.Printf("UpdateCategoryTranslationHandler: Failed to fetch updated category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch updated category",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityCategory, objID, nil, auditSummaryOf(translation), fmt.Sprintf("Translation %s updated", locale))
+	publishEvent(models.EventCategoryUpdated, userEmail, objID, objID, updatedCategory)
+
+	c.Set(fiber.HeaderETag, formatETag(updatedCategory.Version))
+	log.Printf("UpdateCategoryTranslationHandler: Category %s %s translation updated by user %s", id, locale, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Category translation updated successfully",
+		"data":    updatedCategory,
+	})
+}
+
+func UpdateProjectTranslationHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateProjectTranslationHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateProjectTranslationHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	locale, msg := translationLocale(c)
+	if msg != "" {
+		log.Printf("UpdateProjectTranslationHandler: %s", msg)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	var req ProjectTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateProjectTranslationHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	translation := models.ProjectTranslation{
+		Title:       models.NormalizeText(req.Title),
+		Description: models.NormalizeMultiline(req.Description),
+	}
+	if translation.Title == "" && translation.Description == "" {
+		log.Printf("UpdateProjectTranslationHandler: Title and description are missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Title or description is required",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateProjectTranslationHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	return setEntityTranslation(c, "UpdateProjectTranslationHandler", models.RevisionEntityProject, userEmail, locale, translation, version, anyVersion)
+}
+
+func UpdateServiceStepTranslationHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateServiceStepTranslationHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateServiceStepTranslationHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	locale, msg := translationLocale(c)
+	if msg != "" {
+		log.Printf("UpdateServiceStepTranslationHandler: %s", msg)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	var req ServiceStepTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateServiceStepTranslationHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sections := models.NormalizeSections(req.Sections)
+	if len(req.Sections) == 0 {
+		sections = models.SectionsFromFlat(req.Subtitles, req.Headings)
+	}
+	translation := models.ServiceStepTranslation{
+		Title:    models.NormalizeText(req.Title),
+		Sections: sections,
+	}
+	if translation.Title == "" && len(translation.Sections) == 0 {
+		log.Printf("UpdateServiceStepTranslationHandler: Title and sections are missing")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Title or at least one section is required",
+		})
+	}
+
+	version, anyVersion, status, msg := expectedVersion(c, req.Version)
+	if status != 0 {
+		log.Printf("UpdateServiceStepTranslationHandler: %s", msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	return setEntityTranslation(c, "UpdateServiceStepTranslationHandler", models.RevisionEntityServiceStep, userEmail, locale, translation, version, anyVersion)
+}
+
+// setEntityTranslation stores (or with a nil translation removes) one locale of a
+// project or service step as long as it is still at the expected version, bumping the
+// version and recording a revision.
+func setEntityTranslation(c *fiber.Ctx, handler, entityType, userEmail, locale string, translation interface{}, version int64, anyVersion bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	coll, entityID, status, msg := resolveRevisionEntity(ctx, c, entityType)
+	if status != 0 {
+		log.Printf("%s: %s", handler, msg)
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("%s: User %s requested to change %s translation of %s %s", handler, userEmail, locale, entityType, entityID.Hex())
+
+	set := bson.M{"updatedAt": time.Now()}
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}
+	if translation == nil {
+		update["$unset"] = bson.M{"translations." + locale: ""}
+	} else {
+		set["translations."+locale] = translation
+	}
+
+	if err := ensureBaselineRevision(ctx, coll, entityType, entityID); err != nil {
+		log.Printf("%s: Failed to record baseline revision for %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update translation",
+		})
+	}
+
+	updated, err := decodeWithSnapshot(coll.FindOneAndUpdate(ctx, withVersion(bson.M{"_id": entityID}, version, anyVersion), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)), nil)
+	if err == mongo.ErrNoDocuments {
+		if current, err := currentVersion(ctx, coll, bson.M{"_id": entityID}); err == nil {
+			log.Printf("%s: Stale write for %s %s, expected version %d, current %d", handler, entityType, entityID.Hex(), version, current)
+			c.Set(fiber.HeaderETag, formatETag(current))
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   "Translation was modified by someone else",
+				"version": current,
+			})
+		}
+		log.Printf("%s: %s %s not found", handler, entityType, entityID.Hex())
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Entity not found",
+		})
+	}
+	if err != nil {
+		log.Printf("%s: Failed to update %s %s: %v", handler, entityType, entityID.Hex(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update translation",
+		})
+	}
+
+	if _, err := recordRevision(ctx, entityType, entityID, updated, userEmail, nil); err != nil {
+		log.Printf("%s: Failed to record revision for %s %s: %v", handler, entityType, entityID.Hex(), err)
+	}
+
+	// Revision and audit entity types share their names
+	if translation == nil {
+		recordAudit(c, userEmail, models.AuditActionDelete, entityType, entityID, nil, nil, fmt.Sprintf("Translation %s deleted", locale))
+	} else {
+		recordAudit(c, userEmail, models.AuditActionUpdate, entityType, entityID, nil, auditSummaryOf(translation), fmt.Sprintf("Translation %s updated", locale))
+	}
+	categoryID, _ := updated["category_id"].(primitive.ObjectID)
+	publishEvent(revisionEntityEvent(entityType), userEmail, entityID, categoryID, updated)
+
+	if version, ok := updated["version"].(int64); ok {
+		c.Set(fiber.HeaderETag, formatETag(version))
+	}
+	log.Printf("%s: %s %s %s translation changed by user %s", handler, entityType, entityID.Hex(), locale, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Translation updated successfully",
+		"data":    updated["translations"],
+	})
+}
+
+func DeleteCategoryTranslationHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("DeleteCategoryTranslationHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("DeleteCategoryTranslationHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	locale, msg := translationLocale(c)
+	if msg != "" {
+		log.Printf("DeleteCategoryTranslationHandler: %s", msg)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	log.Printf("DeleteCategoryTranslationHandler: User %s requested to delete %s translation of category ID %s", userEmail, locale, id)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("DeleteCategoryTranslationHandler: Invalid category ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := configs.CategoriesColl.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{
+			"$unset": bson.M{"translations." + locale: ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		log.Printf("DeleteCategoryTranslationHandler: Failed to update category ID %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete category translation",
+		})
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("DeleteCategoryTranslationHandler: No category matched for ID %s", id)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityCategory, objID, nil, nil, fmt.Sprintf("Translation %s deleted", locale))
+	publishEvent(models.EventCategoryUpdated, userEmail, objID, objID, fiber.Map{"_id": objID})
+	log.Printf("DeleteCategoryTranslationHandler: Category %s %s translation deleted by user %s", id, locale, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Category translation deleted successfully",
+	})
+}
+
+func deleteEntityTranslation(c *fiber.Ctx, handler, entityType string) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("%s: Failed to get user claims from token", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("%s: Email not found in token claims", handler)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	locale, msg := translationLocale(c)
+	if msg != "" {
+		log.Printf("%s: %s", handler, msg)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	// Deletes need no precondition, but a given If-Match is still honored
+	version, anyVersion := int64(0), true
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		var status int
+		version, anyVersion, status, msg = expectedVersion(c, nil)
+		if status != 0 {
+			log.Printf("%s: %s", handler, msg)
+			return c.Status(status).JSON(fiber.Map{
+				"error": msg,
+			})
+		}
+	}
+
+	return setEntityTranslation(c, handler, entityType, userEmail, locale, nil, version, anyVersion)
+}
+
+func DeleteProjectTranslationHandler(c *fiber.Ctx) error {
+	return deleteEntityTranslation(c, "DeleteProjectTranslationHandler", models.RevisionEntityProject)
+}
+
+func DeleteServiceStepTranslationHandler(c *fiber.Ctx) error {
+	return deleteEntityTranslation(c, "DeleteServiceStepTranslationHandler", models.RevisionEntityServiceStep)
+}
@@ -0,0 +1,538 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxWebhookDeliveryLimit = 100
+
+// WebhookRequest creates or updates a webhook. On update, fields that are left out keep
+// their value; RotateSecret replaces the signing secret.
+type WebhookRequest struct {
+	URL          *string   `json:"url"`
+	Events       *[]string `json:"events"`
+	Description  *string   `json:"description"`
+	Active       *bool     `json:"active"`
+	RotateSecret bool      `json:"rotateSecret"`
+}
+
+// newWebhookSecret returns a random signing secret.
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GetWebhookEventsHandler lists the event types a webhook can subscribe to.
+func GetWebhookEventsHandler(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"message": "Webhook events fetched successfully",
+		"data":    models.EventTypes,
+		"count":   len(models.EventTypes),
+	})
+}
+
+func GetWebhooksHandler(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := configs.WebhooksColl.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		log.Printf("GetWebhooksHandler: Failed to fetch webhooks: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch webhooks",
+		})
+	}
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		log.Printf("GetWebhooksHandler: Failed to process webhooks: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process webhooks",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Webhooks fetched successfully",
+		"data":    webhooks,
+		"count":   len(webhooks),
+	})
+}
+
+func AddWebhookHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("AddWebhookHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("AddWebhookHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("AddWebhookHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook := models.Webhook{
+		Active:    true,
+		CreatedBy: userEmail,
+		CreatedAt: time.Now(),
+		Version:   1,
+	}
+	if req.URL != nil {
+		webhook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		webhook.Events = *req.Events
+	}
+	if req.Description != nil {
+		webhook.Description = models.NormalizeText(*req.Description)
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if err := webhook.Validate(); err != nil {
+		log.Printf("AddWebhookHandler: Invalid webhook: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		log.Printf("AddWebhookHandler: Failed to generate secret: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create webhook",
+		})
+	}
+	webhook.Secret = secret
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := configs.WebhooksColl.InsertOne(ctx, webhook)
+	if err != nil {
+		log.Printf("AddWebhookHandler: Failed to insert webhook: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create webhook",
+		})
+	}
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+	recordAudit(c, userEmail, models.AuditActionCreate, models.AuditEntityWebhook, webhook.ID, nil, auditSummaryOf(webhook), "")
+
+	log.Printf("AddWebhookHandler: Webhook %s for %s created by user %s", webhook.ID.Hex(), webhook.URL, userEmail)
+	// The secret is returned only here and when it is rotated
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Webhook created successfully",
+		"data":    webhook,
+		"secret":  secret,
+	})
+}
+
+func UpdateWebhookHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("UpdateWebhookHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("UpdateWebhookHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("UpdateWebhookHandler: Invalid webhook ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("UpdateWebhookHandler: Failed to parse request body: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var existing models.Webhook
+	if err := configs.WebhooksColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&existing); err != nil {
+		log.Printf("UpdateWebhookHandler: Webhook %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+
+	webhook := existing
+	if req.URL != nil {
+		webhook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		webhook.Events = *req.Events
+	}
+	if req.Description != nil {
+		webhook.Description = models.NormalizeText(*req.Description)
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if err := webhook.Validate(); err != nil {
+		log.Printf("UpdateWebhookHandler: Invalid webhook %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	set := bson.M{
+		"url":         webhook.URL,
+		"events":      webhook.Events,
+		"description": webhook.Description,
+		"active":      webhook.Active,
+		"updatedAt":   time.Now(),
+	}
+	var secret string
+	if req.RotateSecret {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			log.Printf("UpdateWebhookHandler: Failed to generate secret: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update webhook",
+			})
+		}
+		set["secret"] = secret
+	}
+
+	var updated models.Webhook
+	err = configs.WebhooksColl.FindOneAndUpdate(ctx, bson.M{"_id": objID},
+		bson.M{"$set": set, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Webhook not found",
+			})
+		}
+		log.Printf("UpdateWebhookHandler: Failed to update webhook %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update webhook",
+		})
+	}
+
+	detail := ""
+	if req.RotateSecret {
+		detail = "Secret rotated"
+	}
+	recordAudit(c, userEmail, models.AuditActionUpdate, models.AuditEntityWebhook, objID, auditSummaryOf(existing), auditSummaryOf(updated), detail)
+
+	log.Printf("UpdateWebhookHandler: Webhook %s updated by user %s", id, userEmail)
+	response := fiber.Map{
+		"message": "Webhook updated successfully",
+		"data":    updated,
+	}
+	if secret != "" {
+		response["secret"] = secret
+	}
+	return c.JSON(response)
+}
+
+func DeleteWebhookHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("DeleteWebhookHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("DeleteWebhookHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("DeleteWebhookHandler: Invalid webhook ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var webhook models.Webhook
+	if err := configs.WebhooksColl.FindOneAndDelete(ctx, bson.M{"_id": objID}).Decode(&webhook); err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.Printf("DeleteWebhookHandler: Webhook %s not found", id)
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Webhook not found",
+			})
+		}
+		log.Printf("DeleteWebhookHandler: Failed to delete webhook %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete webhook",
+		})
+	}
+
+	// Its delivery log goes with it
+	if _, err := configs.WebhookDeliveriesColl.DeleteMany(ctx, bson.M{"webhookId": objID}); err != nil {
+		log.Printf("DeleteWebhookHandler: Failed to delete deliveries of webhook %s: %v", id, err)
+	}
+	recordAudit(c, userEmail, models.AuditActionDelete, models.AuditEntityWebhook, objID, auditSummaryOf(webhook), nil, "")
+
+	log.Printf("DeleteWebhookHandler: Webhook %s deleted by user %s", id, userEmail)
+	return c.JSON(fiber.Map{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetWebhookDeliveriesHandler lists the deliveries of a webhook, newest first, optionally
+// filtered by status and event.
+func GetWebhookDeliveriesHandler(c *fiber.Ctx) error {
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("GetWebhookDeliveriesHandler: Invalid webhook ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	filter := bson.M{"webhookId": objID}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if event := c.Query("event"); event != "" {
+		filter["event"] = event
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxWebhookDeliveryLimit {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := configs.WebhookDeliveriesColl.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("GetWebhookDeliveriesHandler: Failed to count deliveries of webhook %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch deliveries",
+		})
+	}
+
+	cursor, err := configs.WebhookDeliveriesColl.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		log.Printf("GetWebhookDeliveriesHandler: Failed to fetch deliveries of webhook %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch deliveries",
+		})
+	}
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		log.Printf("GetWebhookDeliveriesHandler: Failed to process deliveries: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process deliveries",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Deliveries fetched successfully",
+		"data":    deliveries,
+		"count":   len(deliveries),
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+// PingWebhookHandler sends a ping event to the webhook right away and returns the
+// delivery, so an endpoint can be checked while it is being set up.
+func PingWebhookHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("PingWebhookHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("PingWebhookHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	id := c.Params("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Printf("PingWebhookHandler: Invalid webhook ID %s: %v", id, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout+5*time.Second)
+	defer cancel()
+
+	var webhook models.Webhook
+	if err := configs.WebhooksColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&webhook); err != nil {
+		log.Printf("PingWebhookHandler: Webhook %s not found: %v", id, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+
+	delivery, err := newWebhookDelivery(webhook, models.Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       models.EventPing,
+		EntityID:   &webhook.ID,
+		Actor:      userEmail,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("PingWebhookHandler: Failed to encode ping: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to ping webhook",
+		})
+	}
+	// Stored as claimed so the dispatcher leaves it alone while it is sent from here
+	lease := time.Now().Add(webhookClaimLease)
+	delivery.NextAttemptAt = &lease
+	result, err := configs.WebhookDeliveriesColl.InsertOne(ctx, delivery)
+	if err != nil {
+		log.Printf("PingWebhookHandler: Failed to store ping delivery: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to ping webhook",
+		})
+	}
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+
+	attempt := sendWebhook(ctx, webhookClient, webhook.URL, webhook.Secret, &delivery)
+	recordWebhookAttempt(ctx, &delivery, attempt, false)
+
+	log.Printf("PingWebhookHandler: Webhook %s pinged by user %s, status %d", id, userEmail, attempt.StatusCode)
+	return c.JSON(fiber.Map{
+		"message": "Webhook pinged",
+		"data":    delivery,
+	})
+}
+
+// RedeliverWebhookHandler queues a new delivery with the payload of an earlier one, for
+// instance after the endpoint was down longer than the retries lasted.
+func RedeliverWebhookHandler(c *fiber.Ctx) error {
+	// Get user email from JWT token
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		log.Printf("RedeliverWebhookHandler: Failed to get user claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+	userEmail, ok := claims["email"].(string)
+	if !ok {
+		log.Printf("RedeliverWebhookHandler: Email not found in token claims")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	deliveryID := c.Params("deliveryId")
+	objID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		log.Printf("RedeliverWebhookHandler: Invalid delivery ID %s: %v", deliveryID, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var original models.WebhookDelivery
+	if err := configs.WebhookDeliveriesColl.FindOne(ctx, bson.M{"_id": objID}).Decode(&original); err != nil {
+		log.Printf("RedeliverWebhookHandler: Delivery %s not found: %v", deliveryID, err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Delivery not found",
+		})
+	}
+
+	now := time.Now()
+	delivery := models.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		Attempts:      []models.WebhookAttempt{},
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+	result, err := configs.WebhookDeliveriesColl.InsertOne(ctx, delivery)
+	if err != nil {
+		log.Printf("RedeliverWebhookHandler: Failed to queue redelivery of %s: %v", deliveryID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue delivery",
+		})
+	}
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	wakeWebhookDispatcher()
+
+	log.Printf("RedeliverWebhookHandler: Delivery %s queued again as %s by user %s", deliveryID, delivery.ID.Hex(), userEmail)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Delivery queued",
+		"data":    delivery,
+	})
+}
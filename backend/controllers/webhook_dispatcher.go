@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	webhookTimeout      = 10 * time.Second
+	webhookPollInterval = 15 * time.Second
+	// webhookClaimLease keeps a delivery away from other dispatch rounds while it is sent
+	webhookClaimLease = 2 * time.Minute
+	// webhookEventQueueSize bounds the published events waiting to become deliveries
+	webhookEventQueueSize = 1024
+	// webhookEventBatchSize is how many waiting events share one webhook lookup and insert
+	webhookEventBatchSize = 100
+)
+
+var (
+	webhookClient = &http.Client{Timeout: webhookTimeout}
+	webhookWake   = make(chan struct{}, 1)
+	webhookEvents = make(chan models.Event, webhookEventQueueSize)
+)
+
+// StartWebhookDispatcher sends queued webhook deliveries in the background. Deliveries
+// live in the database, so retries that were due during a restart are sent afterwards.
+// Published events are turned into deliveries in the background as well.
+func StartWebhookDispatcher() {
+	go storeQueuedWebhookEvents()
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		for {
+			dispatchWebhooks()
+			select {
+			case <-ticker.C:
+			case <-webhookWake:
+			}
+		}
+	}()
+}
+
+// wakeWebhookDispatcher starts a dispatch round without waiting for the next tick.
+func wakeWebhookDispatcher() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// queueWebhookDeliveries hands event to the background queue that stores its deliveries,
+// so publishing does no webhook I/O on the request path. When the queue is full the event
+// is dropped for webhooks, which is logged.
+func queueWebhookDeliveries(event models.Event) {
+	select {
+	case webhookEvents <- event:
+	default:
+		log.Printf("queueWebhookDeliveries: Queue is full, dropping %s event %s", event.Type, event.ID)
+	}
+}
+
+// storeQueuedWebhookEvents stores the deliveries of queued events. Events that are
+// already waiting are taken together, so a bulk change costs one lookup and insert per
+// batch rather than per project.
+func storeQueuedWebhookEvents() {
+	for event := range webhookEvents {
+		batch := []models.Event{event}
+	drain:
+		for len(batch) < webhookEventBatchSize {
+			select {
+			case event := <-webhookEvents:
+				batch = append(batch, event)
+			default:
+				break drain
+			}
+		}
+		storeWebhookDeliveries(batch)
+	}
+}
+
+// storeWebhookDeliveries stores a delivery of each event for every active webhook that
+// subscribed to it.
+func storeWebhookDeliveries(events []models.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := configs.WebhooksColl.Find(ctx, bson.M{"active": true})
+	if err != nil {
+		log.Printf("storeWebhookDeliveries: Failed to fetch webhooks for %d events: %v", len(events), err)
+		return
+	}
+	var webhooks []models.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		log.Printf("storeWebhookDeliveries: Failed to process webhooks for %d events: %v", len(events), err)
+		return
+	}
+
+	deliveries := webhookDeliveriesFor(webhooks, events)
+	if len(deliveries) == 0 {
+		return
+	}
+
+	if _, err := configs.WebhookDeliveriesColl.InsertMany(ctx, deliveries); err != nil {
+		log.Printf("storeWebhookDeliveries: Failed to queue %d deliveries of %d events: %v", len(deliveries), len(events), err)
+		return
+	}
+	wakeWebhookDispatcher()
+}
+
+// webhookDeliveriesFor builds the deliveries of events to the webhooks subscribed to them.
+func webhookDeliveriesFor(webhooks []models.Webhook, events []models.Event) []interface{} {
+	var deliveries []interface{}
+	for _, event := range events {
+		for _, webhook := range webhooks {
+			if !models.EventMatches(webhook.Events, event.Type) {
+				continue
+			}
+			delivery, err := newWebhookDelivery(webhook, event)
+			if err != nil {
+				log.Printf("webhookDeliveriesFor: Failed to encode %s: %v", event.Type, err)
+				break
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries
+}
+
+func newWebhookDelivery(webhook models.Webhook, event models.Event) (models.WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	now := time.Now()
+	return models.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       event.ID,
+		Event:         event.Type,
+		Payload:       string(payload),
+		Status:        models.WebhookDeliveryPending,
+		Attempts:      []models.WebhookAttempt{},
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// dispatchWebhooks sends every delivery that is due, one at a time.
+func dispatchWebhooks() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout+10*time.Second)
+		claimed := dispatchNextWebhook(ctx)
+		cancel()
+		if !claimed {
+			return
+		}
+	}
+}
+
+// dispatchNextWebhook claims the delivery that has been due the longest and attempts it.
+// It reports whether there was one.
+func dispatchNextWebhook(ctx context.Context) bool {
+	now := time.Now()
+	var delivery models.WebhookDelivery
+	err := configs.WebhookDeliveriesColl.FindOneAndUpdate(ctx,
+		bson.M{"status": models.WebhookDeliveryPending, "nextAttemptAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"nextAttemptAt": now.Add(webhookClaimLease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.M{"nextAttemptAt": 1}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("dispatchNextWebhook: Failed to claim a delivery: %v", err)
+		}
+		return false
+	}
+
+	var webhook models.Webhook
+	err = configs.WebhooksColl.FindOne(ctx, bson.M{"_id": delivery.WebhookID}).Decode(&webhook)
+	if err != nil && err != mongo.ErrNoDocuments {
+		// The claim lease runs out and the delivery is picked up again later
+		log.Printf("dispatchNextWebhook: Failed to fetch webhook %s: %v", delivery.WebhookID.Hex(), err)
+		return true
+	}
+	if err == mongo.ErrNoDocuments || !webhook.Active {
+		recordWebhookAttempt(ctx, &delivery, models.WebhookAttempt{
+			At:    now,
+			Error: "Webhook was removed or disabled",
+		}, true)
+		return true
+	}
+
+	attempt := sendWebhook(ctx, webhookClient, webhook.URL, webhook.Secret, &delivery)
+	recordWebhookAttempt(ctx, &delivery, attempt, false)
+	return true
+}
+
+// sendWebhook posts the delivery payload, signed with secret. Any 2xx answer counts as
+// delivered; everything else is returned as the attempt's error.
+func sendWebhook(ctx context.Context, client *http.Client, url, secret string, delivery *models.WebhookDelivery) models.WebhookAttempt {
+	start := time.Now()
+	attempt := models.WebhookAttempt{At: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", models.SignWebhook(secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("Endpoint answered with status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// recordWebhookAttempt appends the attempt to the delivery log and schedules the next one
+// with exponential backoff, until the delivery succeeds or runs out of attempts.
+func recordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt models.WebhookAttempt, giveUp bool) {
+	attempts := len(delivery.Attempts) + 1
+	update := bson.M{"$push": bson.M{"attempts": attempt}}
+	switch {
+	case attempt.Error == "":
+		update["$set"] = bson.M{"status": models.WebhookDeliveryDelivered, "deliveredAt": attempt.At}
+		update["$unset"] = bson.M{"nextAttemptAt": ""}
+	case giveUp || attempts >= models.MaxWebhookAttempts:
+		update["$set"] = bson.M{"status": models.WebhookDeliveryFailed}
+		update["$unset"] = bson.M{"nextAttemptAt": ""}
+	default:
+		update["$set"] = bson.M{"nextAttemptAt": time.Now().Add(models.WebhookBackoff(attempts))}
+	}
+
+	var updated models.WebhookDelivery
+	err := configs.WebhookDeliveriesColl.FindOneAndUpdate(ctx, bson.M{"_id": delivery.ID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		log.Printf("recordWebhookAttempt: Failed to record attempt %d of delivery %s: %v", attempts, delivery.ID.Hex(), err)
+		return
+	}
+	*delivery = updated
+
+	if attempt.Error != "" {
+		log.Printf("recordWebhookAttempt: Delivery %s of %s failed on attempt %d: %s", delivery.ID.Hex(), delivery.Event, attempts, attempt.Error)
+	}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"backend/models"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSendWebhook(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	delivery := &models.WebhookDelivery{
+		ID:      primitive.NewObjectID(),
+		Event:   models.EventProjectCreated,
+		Payload: `{"type":"project.created"}`,
+	}
+
+	attempt := sendWebhook(context.Background(), server.Client(), server.URL, "secret", delivery)
+	if attempt.Error != "" || attempt.StatusCode != http.StatusNoContent {
+		t.Fatalf("attempt = %+v; want a delivered attempt", attempt)
+	}
+	if string(body) != delivery.Payload {
+		t.Errorf("body = %s; want %s", body, delivery.Payload)
+	}
+	if got := received.Header.Get("X-Webhook-Event"); got != models.EventProjectCreated {
+		t.Errorf("X-Webhook-Event = %q", got)
+	}
+	if got := received.Header.Get("X-Webhook-Delivery"); got != delivery.ID.Hex() {
+		t.Errorf("X-Webhook-Delivery = %q; want %q", got, delivery.ID.Hex())
+	}
+	timestamp, err := strconv.ParseInt(received.Header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("X-Webhook-Timestamp: %v", err)
+	}
+	if got, want := received.Header.Get("X-Webhook-Signature"), models.SignWebhook("secret", timestamp, body); got != want {
+		t.Errorf("X-Webhook-Signature = %q; want %q", got, want)
+	}
+
+	status = http.StatusBadGateway
+	attempt = sendWebhook(context.Background(), server.Client(), server.URL, "secret", delivery)
+	if attempt.Error == "" || attempt.StatusCode != http.StatusBadGateway {
+		t.Errorf("attempt = %+v; want a failed attempt with status 502", attempt)
+	}
+
+	server.Close()
+	attempt = sendWebhook(context.Background(), server.Client(), server.URL, "secret", delivery)
+	if attempt.Error == "" || attempt.StatusCode != 0 {
+		t.Errorf("attempt = %+v; want a connection error", attempt)
+	}
+}
+
+func TestWebhookDeliveriesFor(t *testing.T) {
+	projects := models.Webhook{ID: primitive.NewObjectID(), Events: []string{"project.*"}}
+	everything := models.Webhook{ID: primitive.NewObjectID(), Events: []string{"*"}}
+	events := []models.Event{
+		{ID: "1", Type: models.EventProjectCreated},
+		{ID: "2", Type: models.EventCategoryDeleted},
+	}
+
+	deliveries := webhookDeliveriesFor([]models.Webhook{projects, everything}, events)
+	if len(deliveries) != 3 {
+		t.Fatalf("got %d deliveries; want 3", len(deliveries))
+	}
+	want := []struct {
+		webhook primitive.ObjectID
+		event   string
+	}{
+		{projects.ID, "1"},
+		{everything.ID, "1"},
+		{everything.ID, "2"},
+	}
+	for i, w := range want {
+		delivery := deliveries[i].(models.WebhookDelivery)
+		if delivery.WebhookID != w.webhook || delivery.EventID != w.event || delivery.Status != models.WebhookDeliveryPending {
+			t.Errorf("delivery %d = %+v; want webhook %s event %s", i, delivery, w.webhook.Hex(), w.event)
+		}
+	}
+}
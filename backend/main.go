@@ -2,7 +2,10 @@ package main
 
 import (
 	"backend/configs"
+	"backend/controllers"
 	"backend/middleware"
+	"backend/migrations"
+	"backend/notifications"
 	"backend/routers"
 	"context"
 	"log"
@@ -21,9 +24,22 @@ func main() {
 	configs.InitDB()
 	defer configs.DisconnectDB()
 
+	// Apply pending data migrations before serving requests
+	migrations.Run()
+
+	// Notification channels and routes come from the environment
+	notifications.Setup()
+
+	// Send queued webhook deliveries in the background
+	controllers.StartWebhookDispatcher()
+
 	// Initialize Fiber app with configuration
+	trustedProxies := configs.EnvTrustedProxies()
 	app := fiber.New(fiber.Config{
 		BodyLimit: 50 * 1024 * 1024, // 50 MB limit for video uploads
+		// Client IPs come from X-Forwarded-For only behind the proxies in TRUSTED_PROXIES
+		EnableTrustedProxyCheck: len(trustedProxies) > 0,
+		TrustedProxies:          trustedProxies,
 	})
 
 	// Apply middleware
@@ -36,6 +52,16 @@ func main() {
 	// Setup routes
 	routers.AuthRoutes(app)
 	routers.ProjectRoutes(app)
+	routers.SearchRoutes(app)
+	routers.TagRoutes(app)
+	routers.ContactRoutes(app)
+	routers.QuoteRoutes(app)
+	routers.AdminRoutes(app)
+	routers.AnalyticsRoutes(app)
+	routers.WebhookRoutes(app)
+	routers.EventRoutes(app)
+	routers.SeoRoutes(app)
+	routers.SettingsRoutes(app)
 
 	// Handle 404 for undefined routes
 	app.Use(func(c *fiber.Ctx) error {
@@ -69,6 +95,8 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Open event streams would otherwise hold the shutdown until the timeout
+	controllers.CloseEventStreams()
 	if err := app.ShutdownWithContext(ctx); err != nil {
 		log.Printf("Server shutdown failed: %v", err)
 	}
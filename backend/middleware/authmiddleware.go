@@ -30,12 +30,7 @@ func AuthMiddleware() fiber.Handler {
 			})
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid signing method")
-			}
-			return []byte(configs.EnvSecret()), nil
-		})
+		token, err := parseToken(tokenString)
 
 		if err != nil || !token.Valid {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -47,3 +42,44 @@ func AuthMiddleware() fiber.Handler {
 		return c.Next()
 	}
 }
+
+func parseToken(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid signing method")
+		}
+		return []byte(configs.EnvSecret()), nil
+	})
+}
+
+// OptionalAuthMiddleware sets the user claims like AuthMiddleware when a valid token is
+// sent, but lets anonymous requests through. Public endpoints use it to show unpublished
+// content to signed-in admins only.
+func OptionalAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenString := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if tokenString == "" || tokenString == c.Get("Authorization") || IsTokenBlacklisted(tokenString) {
+			return c.Next()
+		}
+
+		token, err := parseToken(tokenString)
+		if err == nil && token.Valid {
+			c.Locals("user", token.Claims)
+		}
+		return c.Next()
+	}
+}
+
+// StreamAuthMiddleware is AuthMiddleware for EventSource connections, which cannot send
+// headers: the token may also be passed in the access_token query parameter.
+func StreamAuthMiddleware() fiber.Handler {
+	auth := AuthMiddleware()
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		return auth(c)
+	}
+}
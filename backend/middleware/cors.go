@@ -44,6 +44,6 @@ func CorsMiddleware() fiber.Handler {
 		AllowOrigins:     "http://localhost:5173, http://localhost:3000, https://dsignme-admin.vercel.app, https://www.dsignme.co",
 		AllowMethods:     "GET,POST,PUT,PATCH,DELETE",
 		AllowCredentials: true,
-		ExposeHeaders:    "Content-Length", // กรณีต้องการ expose header อื่น ๆ
+		ExposeHeaders:    "Content-Length, ETag", // ETag ใช้สำหรับส่ง If-Match ตอนแก้ไขข้อมูล
 	})
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// RateLimitMiddleware allows max requests per client IP within window. Counters are kept
+// in memory, which is enough for the single instance we run on Render.
+func RateLimitMiddleware(max int, window time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return ClientIP(c)
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many requests, please try again later",
+			})
+		},
+	})
+}
+
+// ClientIP returns the address of the client. Behind Render's proxy c.IP() is the proxy,
+// so the last X-Forwarded-For entry, which the proxy appends itself, is used instead. The
+// header is only believed when the request comes from a configured trusted proxy; anyone
+// else could set it to dodge the rate limit.
+func ClientIP(c *fiber.Ctx) string {
+	if c.App().Config().EnableTrustedProxyCheck && c.IsProxyTrusted() {
+		if ips := c.IPs(); len(ips) > 0 {
+			return ips[len(ips)-1]
+		}
+	}
+	return c.IP()
+}
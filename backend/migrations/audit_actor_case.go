@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// auditActorCase lowercases the actor of audit entries written before actors were
+// normalized, so filtering by actor finds them whatever case the email was registered in.
+func auditActorCase(ctx context.Context) error {
+	result, err := configs.AuditColl.UpdateMany(ctx,
+		bson.M{"actor": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"actor": bson.M{"$toLower": "$actor"}}}}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("auditActorCase: Lowercased the actor of %d audit entries", result.ModifiedCount)
+	return nil
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexNotFound is the server error code returned when dropping a missing index.
+const indexNotFound = 27
+
+// categorySiblingNames drops the global unique index on nameCategory. Names are now
+// unique among siblings only, enforced by the {parentId, nameCategory} index in InitDB.
+func categorySiblingNames(ctx context.Context) error {
+	_, err := configs.CategoriesColl.Indexes().DropOne(ctx, "nameCategory_1")
+	if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Code == indexNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// categorySlugs gives every existing category a slug derived from its name,
+// adding a numeric suffix when two names slugify to the same value.
+func categorySlugs(ctx context.Context) error {
+	cursor, err := configs.CategoriesColl.Find(ctx, bson.M{"slug": bson.M{"$not": bson.M{"$type": "string"}}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []models.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		base := models.Slugify(category.NameCategory)
+		if base == "" {
+			base = category.ID.Hex()
+		}
+
+		slug := base
+		for n := 2; ; n++ {
+			count, err := configs.CategoriesColl.CountDocuments(ctx, bson.M{"slug": slug})
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				break
+			}
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		_, err := configs.CategoriesColl.UpdateOne(ctx, bson.M{"_id": category.ID}, bson.M{"$set": bson.M{"slug": slug}})
+		if err != nil {
+			return err
+		}
+		log.Printf("categorySlugs: Category %s got slug %s", category.NameCategory, slug)
+	}
+
+	return nil
+}
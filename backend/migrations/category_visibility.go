@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// categoryVisibility makes categories created before the visibility flag visible,
+// so existing service pages stay listed.
+func categoryVisibility(ctx context.Context) error {
+	result, err := configs.CategoriesColl.UpdateMany(ctx,
+		bson.M{"visible": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"visible": true}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("categoryVisibility: Marked %d categories visible", result.ModifiedCount)
+	return nil
+}
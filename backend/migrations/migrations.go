@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type migration struct {
+	ID string
+	Up func(ctx context.Context) error
+}
+
+// All data migrations in the order they must be applied. Applied IDs are recorded in
+// the migrations collection so each one runs once per database.
+var migrations = []migration{
+	{ID: "0001_service_step_sections", Up: serviceStepSections},
+	{ID: "0002_category_slugs", Up: categorySlugs},
+	{ID: "0003_category_sibling_names", Up: categorySiblingNames},
+	{ID: "0004_category_visibility", Up: categoryVisibility},
+	{ID: "0005_project_published", Up: projectPublished},
+	{ID: "0006_project_slugs", Up: projectSlugs},
+	{ID: "0007_service_step_numbers", Up: serviceStepNumbers},
+	{ID: "0008_audit_actor_case", Up: auditActorCase},
+}
+
+// Run applies pending migrations. It is called once at startup after InitDB.
+func Run() {
+	for _, m := range migrations {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+
+		count, err := configs.MigrationsColl.CountDocuments(ctx, bson.M{"_id": m.ID})
+		if err != nil {
+			cancel()
+			log.Fatal("Failed to check migration "+m.ID+":", err)
+		}
+		if count > 0 {
+			cancel()
+			continue
+		}
+
+		log.Printf("Applying migration %s", m.ID)
+		if err := m.Up(ctx); err != nil {
+			cancel()
+			log.Fatal("Failed to apply migration "+m.ID+":", err)
+		}
+
+		_, err = configs.MigrationsColl.InsertOne(ctx, bson.M{"_id": m.ID, "appliedAt": time.Now()})
+		cancel()
+		if err != nil {
+			log.Fatal("Failed to record migration "+m.ID+":", err)
+		}
+		log.Printf("Migration %s applied", m.ID)
+	}
+}
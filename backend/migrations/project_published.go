@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// projectPublished marks projects created before the publish flag as published,
+// so everything already on the site stays visible.
+func projectPublished(ctx context.Context) error {
+	result, err := configs.ProjectsColl.UpdateMany(ctx,
+		bson.M{"published": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"published": true}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Printf("projectPublished: Marked %d projects published", result.ModifiedCount)
+	return nil
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// projectSlugs gives existing projects with a title a slug derived from it, adding a
+// numeric suffix when two titles in the same category slugify to the same value.
+// Projects without a title stay addressable by ID only.
+func projectSlugs(ctx context.Context) error {
+	cursor, err := configs.ProjectsColl.Find(ctx, bson.M{
+		"slug":  bson.M{"$not": bson.M{"$type": "string"}},
+		"title": bson.M{"$type": "string", "$ne": ""},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		base := models.Slugify(project.Title)
+		if base == "" {
+			continue
+		}
+
+		slug := base
+		for n := 2; ; n++ {
+			count, err := configs.ProjectsColl.CountDocuments(ctx, bson.M{"category_id": project.CategoryID, "slug": slug})
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				break
+			}
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		_, err := configs.ProjectsColl.UpdateOne(ctx, bson.M{"_id": project.ID}, bson.M{"$set": bson.M{"slug": slug}})
+		if err != nil {
+			return err
+		}
+		log.Printf("projectSlugs: Project %s got slug %s", project.ID.Hex(), slug)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"backend/configs"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// serviceStepNumbers numbers the steps of every category 1..n in creation order.
+// Categories whose steps are already numbered 1..n are left as they are, so an
+// order set through the reorder endpoint is kept.
+func serviceStepNumbers(ctx context.Context) error {
+	categoryIDs, err := configs.ServiceStepsColl.Distinct(ctx, "category_id", bson.M{})
+	if err != nil {
+		return err
+	}
+
+	for _, value := range categoryIDs {
+		categoryID, ok := value.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+
+		cursor, err := configs.ServiceStepsColl.Find(ctx,
+			bson.M{"category_id": categoryID},
+			options.Find().
+				SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+				SetProjection(bson.M{"_id": 1, "stepNumber": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		var steps []struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			StepNumber int                `bson:"stepNumber"`
+		}
+		if err := cursor.All(ctx, &steps); err != nil {
+			return err
+		}
+
+		seen := make(map[int]bool, len(steps))
+		for _, step := range steps {
+			if step.StepNumber >= 1 && step.StepNumber <= len(steps) {
+				seen[step.StepNumber] = true
+			}
+		}
+		if len(seen) == len(steps) {
+			continue
+		}
+
+		for i, step := range steps {
+			_, err := configs.ServiceStepsColl.UpdateOne(ctx, bson.M{"_id": step.ID}, bson.M{"$set": bson.M{"stepNumber": i + 1}})
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("serviceStepNumbers: Numbered %d steps of category %s", len(steps), categoryID.Hex())
+	}
+
+	return nil
+}
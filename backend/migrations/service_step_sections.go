@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"backend/configs"
+	"backend/models"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// serviceStepSections converts the flat subtitles/headings arrays of existing
+// service steps into nested sections.
+func serviceStepSections(ctx context.Context) error {
+	cursor, err := configs.ServiceStepsColl.Find(ctx, bson.M{"sections": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	converted := 0
+	for cursor.Next(ctx) {
+		var legacy struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Subtitles []string           `bson:"subtitles"`
+			Headings  []string           `bson:"headings"`
+		}
+		if err := cursor.Decode(&legacy); err != nil {
+			return err
+		}
+
+		sections := models.SectionsFromFlat(legacy.Subtitles, legacy.Headings)
+		_, err := configs.ServiceStepsColl.UpdateOne(ctx,
+			bson.M{"_id": legacy.ID},
+			bson.M{
+				"$set":   bson.M{"sections": sections},
+				"$unset": bson.M{"subtitles": "", "headings": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		converted++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("serviceStepSections: Converted %d service steps", converted)
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Analytics event types sent by the public site.
+const (
+	AnalyticsEventView      = "view"
+	AnalyticsEventModalOpen = "modal_open"
+	AnalyticsEventVideoPlay = "video_play"
+)
+
+var analyticsCounterFields = map[string]string{
+	AnalyticsEventView:      "views",
+	AnalyticsEventModalOpen: "modalOpens",
+	AnalyticsEventVideoPlay: "videoPlays",
+}
+
+// AnalyticsCounts are the totals of each event type.
+type AnalyticsCounts struct {
+	Views      int `bson:"views" json:"views"`
+	ModalOpens int `bson:"modalOpens" json:"modalOpens"`
+	VideoPlays int `bson:"videoPlays" json:"videoPlays"`
+}
+
+// AnalyticsDaily is the rollup of one project's events on one day (YYYY-MM-DD, studio time).
+// Visitors counts distinct hashed visitors; no per-visitor data is kept in the rollup.
+type AnalyticsDaily struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	ProjectID  primitive.ObjectID `bson:"project_id" json:"projectId"`
+	CategoryID primitive.ObjectID `bson:"category_id" json:"categoryId"`
+	Date       string             `bson:"date" json:"date"`
+	Counts     AnalyticsCounts    `bson:"counts" json:"counts"`
+	Visitors   int                `bson:"visitors" json:"visitors"`
+}
+
+// AnalyticsCounterField returns the AnalyticsCounts field an event type increments.
+func AnalyticsCounterField(eventType string) (string, bool) {
+	field, ok := analyticsCounterFields[eventType]
+	return field, ok
+}
+
+// VisitorHash derives an anonymous visitor ID from the client's IP address and user
+// agent. The day is part of the key, so the same visitor gets a new ID every day and
+// cannot be followed across days; the IP address itself is never stored.
+func VisitorHash(secret, day, ip, userAgent string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(day + "\n" + ip + "\n" + userAgent))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var botMarkers = []string{"bot", "crawl", "spider", "slurp", "headless", "preview"}
+
+// IsBotUserAgent reports whether a user agent is missing or looks like a crawler, whose
+// requests are not counted.
+func IsBotUserAgent(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if strings.TrimSpace(ua) == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
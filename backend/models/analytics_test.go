@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+func TestVisitorHash(t *testing.T) {
+	a := VisitorHash("secret", "2024-05-01", "203.0.113.7", "Mozilla/5.0")
+	if a != VisitorHash("secret", "2024-05-01", "203.0.113.7", "Mozilla/5.0") {
+		t.Errorf("VisitorHash is not stable within a day")
+	}
+	if a == VisitorHash("secret", "2024-05-02", "203.0.113.7", "Mozilla/5.0") {
+		t.Errorf("VisitorHash did not change with the day")
+	}
+	if len(a) != 64 {
+		t.Errorf("VisitorHash length = %d; want 64", len(a))
+	}
+}
+
+func TestAnalyticsCounterField(t *testing.T) {
+	if field, ok := AnalyticsCounterField(AnalyticsEventModalOpen); !ok || field != "modalOpens" {
+		t.Errorf("AnalyticsCounterField(modal_open) = %q, %v", field, ok)
+	}
+	if _, ok := AnalyticsCounterField("click"); ok {
+		t.Errorf("AnalyticsCounterField(click) is known; want unknown")
+	}
+}
+
+func TestIsBotUserAgent(t *testing.T) {
+	tests := []struct {
+		ua       string
+		expected bool
+	}{
+		{"", true},
+		{"Googlebot/2.1 (+http://www.google.com/bot.html)", true},
+		{"facebookexternalhit/1.1 LinePreview", true},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Line/13.0", false},
+	}
+	for _, test := range tests {
+		if got := IsBotUserAgent(test.ua); got != test.expected {
+			t.Errorf("IsBotUserAgent(%q) = %v; want %v", test.ua, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionMove    = "move"
+	AuditActionCopy    = "copy"
+	AuditActionReorder = "reorder"
+	AuditActionRestore = "restore"
+	AuditActionUpload  = "upload"
+	AuditActionLogin   = "login"
+)
+
+const (
+	AuditEntityUser         = "user"
+	AuditEntityCategory     = "category"
+	AuditEntityProject      = "project"
+	AuditEntityServiceStep  = "serviceStep"
+	AuditEntityFile         = "file"
+	AuditEntityTag          = "tag"
+	AuditEntityWebhook      = "webhook"
+	AuditEntitySiteSettings = "siteSettings"
+)
+
+// maxAuditText caps how much of a text field is kept in an audit summary.
+const maxAuditText = 200
+
+// auditSkipFields never end up in the audit log: secrets, and bookkeeping that changes
+// on every write.
+var auditSkipFields = map[string]bool{
+	"password":  true,
+	"secret":    true,
+	"updatedAt": true,
+	"version":   true,
+}
+
+// AuditEntry records one administrative change. Before and After summarize the entity;
+// for updates they only hold the fields that changed.
+type AuditEntry struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Actor      string              `bson:"actor" json:"actor"`
+	Action     string              `bson:"action" json:"action"`
+	EntityType string              `bson:"entityType" json:"entityType"`
+	EntityID   *primitive.ObjectID `bson:"entityId,omitempty" json:"entityId,omitempty"`
+	Detail     string              `bson:"detail,omitempty" json:"detail,omitempty"`
+	Before     bson.M              `bson:"before,omitempty" json:"before,omitempty"`
+	After      bson.M              `bson:"after,omitempty" json:"after,omitempty"`
+	IP         string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string              `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// AuditSummary keeps the top-level fields of a document that are useful in an audit
+// trail. Long texts are truncated, nested documents are reduced to their sorted keys and
+// lists of documents to their length, so entries stay small.
+func AuditSummary(doc bson.M) bson.M {
+	if doc == nil {
+		return nil
+	}
+	summary := bson.M{}
+	for field, value := range doc {
+		if auditSkipFields[field] {
+			continue
+		}
+		summary[field] = auditValue(value)
+	}
+	return summary
+}
+
+func auditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if utf8.RuneCountInString(v) > maxAuditText {
+			return string([]rune(v)[:maxAuditText]) + "…"
+		}
+		return v
+	case bson.M:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	case bson.D:
+		return auditValue(v.Map())
+	case bson.A:
+		for _, item := range v {
+			switch item.(type) {
+			case bson.M, bson.D, bson.A:
+				return len(v)
+			}
+		}
+		return v
+	}
+	return value
+}
+
+// AuditChanges reduces two summaries to the fields that differ between them.
+func AuditChanges(before, after bson.M) (bson.M, bson.M) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore, changedAfter := bson.M{}, bson.M{}
+	for field, value := range before {
+		if other, ok := after[field]; !ok || !reflect.DeepEqual(value, other) {
+			changedBefore[field] = value
+		}
+	}
+	for field, value := range after {
+		if other, ok := before[field]; !ok || !reflect.DeepEqual(value, other) {
+			changedAfter[field] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
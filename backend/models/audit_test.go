@@ -0,0 +1,58 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAuditSummary(t *testing.T) {
+	summary := AuditSummary(bson.M{
+		"email":        "admin@example.com",
+		"password":     "$2a$10$hash",
+		"version":      int64(3),
+		"description":  strings.Repeat("ก", 250),
+		"translations": bson.M{"en": bson.M{"title": "Logo"}, "de": bson.M{}},
+		"sections":     bson.A{bson.M{"text": "a"}, bson.M{"text": "b"}},
+		"tags":         bson.A{"branding", "print"},
+	})
+
+	if _, ok := summary["password"]; ok {
+		t.Errorf("AuditSummary kept the password")
+	}
+	if _, ok := summary["version"]; ok {
+		t.Errorf("AuditSummary kept the version")
+	}
+	if got := []rune(summary["description"].(string)); len(got) != maxAuditText+1 {
+		t.Errorf("description has %d runes; want %d", len(got), maxAuditText+1)
+	}
+	if got := summary["translations"]; !reflect.DeepEqual(got, []string{"de", "en"}) {
+		t.Errorf("translations = %v; want [de en]", got)
+	}
+	if got := summary["sections"]; got != 2 {
+		t.Errorf("sections = %v; want 2", got)
+	}
+	if got := summary["tags"]; !reflect.DeepEqual(got, bson.A{"branding", "print"}) {
+		t.Errorf("tags = %v", got)
+	}
+}
+
+func TestAuditChanges(t *testing.T) {
+	before, after := AuditChanges(
+		bson.M{"title": "Old", "published": true, "slug": "old"},
+		bson.M{"title": "New", "published": true, "slug": "old", "pinned": true},
+	)
+	if !reflect.DeepEqual(before, bson.M{"title": "Old"}) {
+		t.Errorf("before = %v", before)
+	}
+	if !reflect.DeepEqual(after, bson.M{"title": "New", "pinned": true}) {
+		t.Errorf("after = %v", after)
+	}
+
+	before, after = AuditChanges(nil, bson.M{"title": "New"})
+	if before != nil || after["title"] != "New" {
+		t.Errorf("AuditChanges(nil, doc) = %v, %v", before, after)
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits for landing page fields, counted in characters. The SEO limits follow
+// what search engines show before truncating.
+const (
+	MaxCategoryDescriptionLength = 2000
+	MaxSeoTitleLength            = 70
+	MaxSeoDescriptionLength      = 160
+)
+
+// IsMediaURL reports whether s is an absolute http(s) URL or a site-relative path,
+// the two forms produced by the upload endpoint and used for external media.
+func IsMediaURL(s string) bool {
+	if strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ValidateMetadata checks the landing page fields of a category. Empty fields are allowed.
+func (c Category) ValidateMetadata() error {
+	if utf8.RuneCountInString(c.Description) > MaxCategoryDescriptionLength {
+		return errors.New("Description is too long")
+	}
+	if utf8.RuneCountInString(c.SeoTitle) > MaxSeoTitleLength {
+		return errors.New("SEO title must be at most 70 characters")
+	}
+	if utf8.RuneCountInString(c.SeoDescription) > MaxSeoDescriptionLength {
+		return errors.New("SEO description must be at most 160 characters")
+	}
+	for name, value := range map[string]string{
+		"Hero media URL":  c.HeroMediaUrl,
+		"Cover image URL": c.CoverImageUrl,
+	} {
+		if value != "" && !IsMediaURL(value) {
+			return errors.New(name + " must be an http(s) URL or a site path")
+		}
+	}
+	if c.DisplayOrder < 0 {
+		return errors.New("Display order cannot be negative")
+	}
+	return nil
+}
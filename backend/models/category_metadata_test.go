@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsMediaURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://api.example.com/files/65f1c0a2b3d4e5f6a7b8c9d0": true,
+		"http://localhost:5000/files/abc":                        true,
+		"/images/hero.webp":                                      true,
+		"//evil.example.com/x.png":                               false,
+		"javascript:alert(1)":                                    false,
+		"ftp://example.com/a.png":                                false,
+		"hero.png":                                               false,
+	}
+	for input, want := range tests {
+		if got := IsMediaURL(input); got != want {
+			t.Errorf("IsMediaURL(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestCategoryValidateMetadata(t *testing.T) {
+	valid := Category{
+		Description:    "ออกแบบโลโก้สำหรับร้านอาหาร",
+		SeoTitle:       strings.Repeat("ก", MaxSeoTitleLength),
+		SeoDescription: "Logo design",
+		HeroMediaUrl:   "https://example.com/files/1",
+		Icon:           "palette",
+		DisplayOrder:   2,
+	}
+	if err := valid.ValidateMetadata(); err != nil {
+		t.Fatalf("valid metadata rejected: %v", err)
+	}
+
+	tooLong := valid
+	tooLong.SeoTitle += "ก"
+	if tooLong.ValidateMetadata() == nil {
+		t.Error("SEO title over the limit accepted")
+	}
+
+	badURL := valid
+	badURL.CoverImageUrl = "javascript:alert(1)"
+	if badURL.ValidateMetadata() == nil {
+		t.Error("non-http cover image URL accepted")
+	}
+
+	negative := valid
+	negative.DisplayOrder = -1
+	if negative.ValidateMetadata() == nil {
+		t.Error("negative display order accepted")
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryNode is a category with its subcategories, as returned by the tree endpoint.
+type CategoryNode struct {
+	Category
+	Children []CategoryNode
+}
+
+// BuildCategoryTree arranges a flat category list into trees. Categories whose parent
+// is missing from the list are treated as roots so a dangling reference never hides one.
+func BuildCategoryTree(categories []Category) []CategoryNode {
+	known := make(map[primitive.ObjectID]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	children := map[primitive.ObjectID][]Category{}
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentID == nil || !known[*category.ParentID] || *category.ParentID == category.ID {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	visited := map[primitive.ObjectID]bool{}
+	var build func(list []Category) []CategoryNode
+	build = func(list []Category) []CategoryNode {
+		sortCategories(list)
+		nodes := make([]CategoryNode, 0, len(list))
+		for _, category := range list {
+			if visited[category.ID] {
+				continue
+			}
+			visited[category.ID] = true
+			nodes = append(nodes, CategoryNode{Category: category, Children: build(children[category.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// sortCategories orders siblings by display order, then name so the tree is stable between requests.
+func sortCategories(list []Category) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].DisplayOrder != list[j].DisplayOrder {
+			return list[i].DisplayOrder < list[j].DisplayOrder
+		}
+		return list[i].NameCategory < list[j].NameCategory
+	})
+}
+
+// CategoryDescendantIDs returns rootID followed by the IDs of all its descendants.
+func CategoryDescendantIDs(categories []Category, rootID primitive.ObjectID) []primitive.ObjectID {
+	children := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []primitive.ObjectID{rootID}
+	seen := map[primitive.ObjectID]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// VisibleCategories drops hidden categories together with everything below them, so a
+// hidden service also hides its subcategories from the public site.
+func VisibleCategories(categories []Category) []Category {
+	hidden := map[primitive.ObjectID]bool{}
+	for _, category := range categories {
+		if !category.Visible {
+			for _, id := range CategoryDescendantIDs(categories, category.ID) {
+				hidden[id] = true
+			}
+		}
+	}
+
+	visible := make([]Category, 0, len(categories))
+	for _, category := range categories {
+		if !hidden[category.ID] {
+			visible = append(visible, category)
+		}
+	}
+	return visible
+}
+
+// CategoryParentCreatesCycle reports whether making parentID the parent of id would
+// put id inside its own subtree.
+func CategoryParentCreatesCycle(categories []Category, id, parentID primitive.ObjectID) bool {
+	for _, descendant := range CategoryDescendantIDs(categories, id) {
+		if descendant == parentID {
+			return true
+		}
+	}
+	return false
+}
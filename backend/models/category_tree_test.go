@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func categoryWithParent(name string, parent *Category) Category {
+	category := Category{ID: primitive.NewObjectID(), NameCategory: name, Visible: true}
+	if parent != nil {
+		category.ParentID = &parent.ID
+	}
+	return category
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	website := categoryWithParent("Website", nil)
+	logo := categoryWithParent("Logo", nil)
+	shop := categoryWithParent("E-commerce", &website)
+	restaurant := categoryWithParent("Restaurant", &logo)
+	missing := primitive.NewObjectID()
+	orphan := Category{ID: primitive.NewObjectID(), NameCategory: "Orphan", ParentID: &missing}
+
+	tree := BuildCategoryTree([]Category{shop, website, restaurant, logo, orphan})
+	if len(tree) != 3 {
+		t.Fatalf("got %d roots, want 3", len(tree))
+	}
+	if tree[0].NameCategory != "Logo" || tree[1].NameCategory != "Orphan" || tree[2].NameCategory != "Website" {
+		t.Fatalf("roots not sorted by name: %s, %s, %s", tree[0].NameCategory, tree[1].NameCategory, tree[2].NameCategory)
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].ID != restaurant.ID {
+		t.Fatalf("Logo children = %+v, want Restaurant", tree[0].Children)
+	}
+	if len(tree[2].Children) != 1 || tree[2].Children[0].ID != shop.ID {
+		t.Fatalf("Website children = %+v, want E-commerce", tree[2].Children)
+	}
+}
+
+func TestCategoryDescendantIDs(t *testing.T) {
+	root := categoryWithParent("Website", nil)
+	child := categoryWithParent("E-commerce", &root)
+	grandchild := categoryWithParent("Fashion", &child)
+	other := categoryWithParent("Logo", nil)
+	categories := []Category{root, child, grandchild, other}
+
+	ids := CategoryDescendantIDs(categories, root.ID)
+	if len(ids) != 3 || ids[0] != root.ID {
+		t.Fatalf("got %v, want root, child and grandchild", ids)
+	}
+	for _, id := range ids {
+		if id == other.ID {
+			t.Fatalf("unrelated category %s included", other.NameCategory)
+		}
+	}
+}
+
+func TestCategoryParentCreatesCycle(t *testing.T) {
+	root := categoryWithParent("Website", nil)
+	child := categoryWithParent("E-commerce", &root)
+	grandchild := categoryWithParent("Fashion", &child)
+	other := categoryWithParent("Logo", nil)
+	categories := []Category{root, child, grandchild, other}
+
+	tests := []struct {
+		name     string
+		id       primitive.ObjectID
+		parentID primitive.ObjectID
+		want     bool
+	}{
+		{"self", root.ID, root.ID, true},
+		{"under own grandchild", root.ID, grandchild.ID, true},
+		{"under unrelated", root.ID, other.ID, false},
+		{"grandchild up to root", grandchild.ID, root.ID, false},
+	}
+	for _, tt := range tests {
+		if got := CategoryParentCreatesCycle(categories, tt.id, tt.parentID); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVisibleCategories(t *testing.T) {
+	website := categoryWithParent("Website", nil)
+	website.Visible = false
+	shop := categoryWithParent("E-commerce", &website)
+	logo := categoryWithParent("Logo", nil)
+	restaurant := categoryWithParent("Restaurant", &logo)
+
+	visible := VisibleCategories([]Category{website, shop, logo, restaurant})
+	if len(visible) != 2 || visible[0].ID != logo.ID || visible[1].ID != restaurant.ID {
+		t.Fatalf("got %+v, want Logo and Restaurant only", visible)
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Contact statuses match ContactStatus in the admin dashboard.
+const (
+	ContactStatusNew     = "New"
+	ContactStatusReplied = "Replied"
+	ContactStatusPending = "Pending"
+	ContactStatusClosed  = "Closed"
+)
+
+const (
+	MaxContactNameLength    = 100
+	MaxContactBudgetLength  = 100
+	MaxContactMessageLength = 5000
+	MaxContactNoteLength    = 2000
+)
+
+var contactPhonePattern = regexp.MustCompile(`^\+?[0-9][0-9 \-]{6,19}$`)
+
+// Contact is a lead sent through the public contact form.
+type Contact struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name       string              `bson:"name" json:"name"`
+	Email      string              `bson:"email" json:"email"`
+	Phone      string              `bson:"phone,omitempty" json:"phone,omitempty"`
+	CategoryID *primitive.ObjectID `bson:"category_id,omitempty" json:"categoryId,omitempty"`
+	Budget     string              `bson:"budget,omitempty" json:"budget,omitempty"`
+	Message    string              `bson:"message" json:"message"`
+	Status     string              `bson:"status" json:"status"`
+	AssignedTo string              `bson:"assignedTo,omitempty" json:"assignedTo,omitempty"`
+	Notes      []ContactNote       `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  *time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version    int64               `bson:"version" json:"version"`
+}
+
+// ContactNote is an internal note on a lead; it is never shown to the client.
+type ContactNote struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	Author    string             `bson:"author" json:"author"`
+	Text      string             `bson:"text" json:"text"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// IsContactStatus reports whether status is one of the known contact statuses.
+func IsContactStatus(status string) bool {
+	switch status {
+	case ContactStatusNew, ContactStatusReplied, ContactStatusPending, ContactStatusClosed:
+		return true
+	}
+	return false
+}
+
+// ValidateContactDetails checks how a visitor can be reached. Name and email are required;
+// phone is optional but must look like a phone number when given.
+func ValidateContactDetails(name, email, phone string) error {
+	if name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if utf8.RuneCountInString(name) > MaxContactNameLength {
+		return fmt.Errorf("Name must be at most %d characters", MaxContactNameLength)
+	}
+	if !(&User{Email: email}).IsEmailValid() {
+		return fmt.Errorf("Invalid email format")
+	}
+	if phone != "" && !contactPhonePattern.MatchString(phone) {
+		return fmt.Errorf("Invalid phone number")
+	}
+	return nil
+}
+
+// Validate checks the fields a visitor fills in on the contact form; the message is required.
+func (c *Contact) Validate() error {
+	if err := ValidateContactDetails(c.Name, c.Email, c.Phone); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(c.Budget) > MaxContactBudgetLength {
+		return fmt.Errorf("Budget must be at most %d characters", MaxContactBudgetLength)
+	}
+	if strings.TrimSpace(c.Message) == "" {
+		return fmt.Errorf("Message is required")
+	}
+	if utf8.RuneCountInString(c.Message) > MaxContactMessageLength {
+		return fmt.Errorf("Message must be at most %d characters", MaxContactMessageLength)
+	}
+	return nil
+}
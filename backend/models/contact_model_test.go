@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsContactStatus(t *testing.T) {
+	for _, status := range []string{"New", "Replied", "Pending", "Closed"} {
+		if !IsContactStatus(status) {
+			t.Errorf("IsContactStatus(%q) = false; want true", status)
+		}
+	}
+	for _, status := range []string{"", "new", "Open"} {
+		if IsContactStatus(status) {
+			t.Errorf("IsContactStatus(%q) = true; want false", status)
+		}
+	}
+}
+
+func TestContactValidate(t *testing.T) {
+	valid := Contact{Name: "สมชาย", Email: "somchai@example.co.th", Phone: "+66 81-234-5678", Message: "สนใจงานออกแบบ"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v; want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Contact)
+	}{
+		{"missing name", func(c *Contact) { c.Name = "" }},
+		{"bad email", func(c *Contact) { c.Email = "somchai@" }},
+		{"bad phone", func(c *Contact) { c.Phone = "call me" }},
+		{"blank message", func(c *Contact) { c.Message = "   " }},
+		{"long message", func(c *Contact) { c.Message = strings.Repeat("ก", MaxContactMessageLength+1) }},
+	}
+	for _, test := range tests {
+		contact := valid
+		test.change(&contact)
+		if err := contact.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil; want an error", test.name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event types published after successful writes, named <entity>.<change>.
+const (
+	EventCategoryCreated    = "category.created"
+	EventCategoryUpdated    = "category.updated"
+	EventCategoryDeleted    = "category.deleted"
+	EventProjectCreated     = "project.created"
+	EventProjectUpdated     = "project.updated"
+	EventProjectDeleted     = "project.deleted"
+	EventServiceStepCreated = "servicestep.created"
+	EventServiceStepUpdated = "servicestep.updated"
+	EventServiceStepDeleted = "servicestep.deleted"
+	EventTagCreated         = "tag.created"
+	EventTagUpdated         = "tag.updated"
+	EventTagDeleted         = "tag.deleted"
+	EventContactCreated     = "contact.created"
+	EventQuoteCreated       = "quote.created"
+	EventFileUploaded       = "file.uploaded"
+	EventSettingsUpdated    = "settings.updated"
+	EventPing               = "ping"
+)
+
+// EventTypes lists every event that can be subscribed to.
+var EventTypes = []string{
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted,
+	EventProjectCreated, EventProjectUpdated, EventProjectDeleted,
+	EventServiceStepCreated, EventServiceStepUpdated, EventServiceStepDeleted,
+	EventTagCreated, EventTagUpdated, EventTagDeleted,
+	EventContactCreated, EventQuoteCreated, EventFileUploaded,
+	EventSettingsUpdated,
+}
+
+// Event describes a change to the content. Data holds the entity after the change, or
+// just its ID once it has been deleted.
+type Event struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	EntityID   *primitive.ObjectID `json:"entityId,omitempty"`
+	CategoryID *primitive.ObjectID `json:"categoryId,omitempty"`
+	Actor      string              `json:"actor,omitempty"`
+	Data       interface{}         `json:"data,omitempty"`
+	OccurredAt time.Time           `json:"occurredAt"`
+}
+
+// IsEventFilter reports whether filter names an event type, every event of an entity
+// ("project.*") or every event ("*").
+func IsEventFilter(filter string) bool {
+	if filter == "*" {
+		return true
+	}
+	for _, eventType := range EventTypes {
+		if filter == eventType || filter == eventEntity(eventType)+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// EventMatches reports whether eventType is selected by any of the filters.
+func EventMatches(filters []string, eventType string) bool {
+	for _, filter := range filters {
+		if filter == "*" || filter == eventType || filter == eventEntity(eventType)+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// SSEFrame formats one server-sent event without an event name, so clients receive it in
+// onmessage. Every line of data gets its own data field, as a field ends at a newline.
+func SSEFrame(id string, data []byte) string {
+	var frame strings.Builder
+	if id != "" {
+		frame.WriteString("id: " + id + "\n")
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		frame.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	frame.WriteString("\n")
+	return frame.String()
+}
+
+func eventEntity(eventType string) string {
+	if i := strings.IndexByte(eventType, '.'); i >= 0 {
+		return eventType[:i]
+	}
+	return eventType
+}
@@ -0,0 +1,17 @@
+package models
+
+import "testing"
+
+func TestSSEFrame(t *testing.T) {
+	got := SSEFrame("42", []byte(`{"type":"project.created"}`))
+	want := "id: 42\ndata: {\"type\":\"project.created\"}\n\n"
+	if got != want {
+		t.Errorf("SSEFrame = %q; want %q", got, want)
+	}
+
+	got = SSEFrame("", []byte("line one\r\nline two"))
+	want = "data: line one\ndata: line two\n\n"
+	if got != want {
+		t.Errorf("SSEFrame = %q; want %q", got, want)
+	}
+}
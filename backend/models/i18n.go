@@ -0,0 +1,129 @@
+package models
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultLocale is the language of the base content fields; other locales live in Translations.
+const DefaultLocale = "th"
+
+// SupportedLocales lists the locales content can be translated into, default first.
+var SupportedLocales = []string{"th", "en"}
+
+var localeMatcher = language.NewMatcher([]language.Tag{language.Thai, language.English})
+
+type CategoryTranslation struct {
+	NameCategory   string `bson:"nameCategory" json:"nameCategory"`
+	Description    string `bson:"description,omitempty" json:"description,omitempty"`
+	SeoTitle       string `bson:"seoTitle,omitempty" json:"seoTitle,omitempty"`
+	SeoDescription string `bson:"seoDescription,omitempty" json:"seoDescription,omitempty"`
+}
+
+type ProjectTranslation struct {
+	Title       string `bson:"title" json:"title"`
+	Description string `bson:"description" json:"description"`
+}
+
+type ServiceStepTranslation struct {
+	Title    string     `bson:"title" json:"title"`
+	Sections []Subtitle `bson:"sections" json:"sections"`
+}
+
+// IsLocaleSupported reports whether locale is one of SupportedLocales.
+func IsLocaleSupported(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchLocale picks the response locale: an explicit lang value wins, then the
+// best match from an Accept-Language header, then DefaultLocale.
+func MatchLocale(lang, acceptLanguage string) string {
+	if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			base, _ := tag.Base()
+			if IsLocaleSupported(base.String()) {
+				return base.String()
+			}
+		}
+	}
+
+	if acceptLanguage != "" {
+		tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+		if err == nil && len(tags) > 0 {
+			_, index, confidence := localeMatcher.Match(tags...)
+			if confidence != language.No {
+				return SupportedLocales[index]
+			}
+		}
+	}
+
+	return DefaultLocale
+}
+
+// NormalizeText puts user-entered text into NFC form and collapses runs of
+// whitespace, without changing letter case. Thai input methods can produce the
+// same word with different code point sequences; NFC makes them compare equal.
+func NormalizeText(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// NormalizeMultiline is NormalizeText for longer text: it applies NFC and trims the
+// ends but keeps line breaks and paragraph spacing.
+func NormalizeMultiline(s string) string {
+	return strings.TrimSpace(norm.NFC.String(s))
+}
+
+// Localized returns the category with its name, description and SEO fields in the
+// requested locale; each field falls back to the base content on its own.
+func (c Category) Localized(locale string) Category {
+	if t, ok := c.Translations[locale]; ok {
+		if t.NameCategory != "" {
+			c.NameCategory = t.NameCategory
+		}
+		if t.Description != "" {
+			c.Description = t.Description
+		}
+		if t.SeoTitle != "" {
+			c.SeoTitle = t.SeoTitle
+		}
+		if t.SeoDescription != "" {
+			c.SeoDescription = t.SeoDescription
+		}
+	}
+	return c
+}
+
+// Localized returns the project with Title and Description in the requested
+// locale; each field falls back to the base content on its own.
+func (p Project) Localized(locale string) Project {
+	if t, ok := p.Translations[locale]; ok {
+		if t.Title != "" {
+			p.Title = t.Title
+		}
+		if t.Description != "" {
+			p.Description = t.Description
+		}
+	}
+	return p
+}
+
+// Localized returns the service step with Title and Sections in the requested
+// locale; each field falls back to the base content on its own.
+func (s ServiceStep) Localized(locale string) ServiceStep {
+	if t, ok := s.Translations[locale]; ok {
+		if t.Title != "" {
+			s.Title = t.Title
+		}
+		if len(t.Sections) > 0 {
+			s.Sections = t.Sections
+		}
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		lang           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"", "", "th"},
+		{"en", "th-TH", "en"},
+		{"EN-us", "", "en"},
+		{"fr", "en-US,en;q=0.9", "en"},
+		{"", "fr-FR,fr;q=0.9,en;q=0.5", "en"},
+		{"", "th-TH,th;q=0.9,en;q=0.8", "th"},
+		{"", "ja", "th"},
+		{"xx-invalid-tag-", "", "th"},
+	}
+
+	for _, test := range tests {
+		result := MatchLocale(test.lang, test.acceptLanguage)
+		if result != test.expected {
+			t.Errorf("MatchLocale(%q, %q) = %q; want %q", test.lang, test.acceptLanguage, result, test.expected)
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	decomposed := "Cafe\u0301  Logo "
+	if got := NormalizeText(decomposed); got != "Caf\u00e9 Logo" {
+		t.Errorf("NormalizeText(%q) = %q; want %q", decomposed, got, "Caf\u00e9 Logo")
+	}
+	if got := NormalizeText("โลโก้\tร้านอาหาร"); got != "โลโก้ ร้านอาหาร" {
+		t.Errorf("NormalizeText kept tab: %q", got)
+	}
+}
+
+func TestLocalizedFallback(t *testing.T) {
+	p := Project{
+		Title:       "โลโก้",
+		Description: "คำอธิบาย",
+		Translations: map[string]ProjectTranslation{
+			"en": {Title: "Logo"},
+		},
+	}
+
+	en := p.Localized("en")
+	if en.Title != "Logo" || en.Description != "คำอธิบาย" {
+		t.Errorf("Localized(en) = %q/%q; want Logo with Thai description fallback", en.Title, en.Description)
+	}
+	if th := p.Localized("th"); th.Title != "โลโก้" {
+		t.Errorf("Localized(th) title = %q; want base title", th.Title)
+	}
+}
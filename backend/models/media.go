@@ -0,0 +1,35 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// YouTubeVideoID extracts the video ID from the YouTube URL forms editors paste
+// (watch, youtu.be, embed and shorts links). It returns "" for anything else.
+func YouTubeVideoID(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	path := strings.Trim(u.Path, "/")
+
+	var id string
+	switch host {
+	case "youtu.be":
+		id = path
+	case "youtube.com", "youtube-nocookie.com":
+		if path == "watch" {
+			id = u.Query().Get("v")
+		} else if parts := strings.Split(path, "/"); len(parts) == 2 && (parts[0] == "embed" || parts[0] == "shorts" || parts[0] == "live") {
+			id = parts[1]
+		}
+	}
+
+	if id == "" || strings.Contains(id, "/") {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestYouTubeVideoID(t *testing.T) {
+	tests := map[string]string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":          "dQw4w9WgXcQ",
+		"https://youtube.com/watch?v=dQw4w9WgXcQ&t=42s":        "dQw4w9WgXcQ",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ":            "dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ":                         "dQw4w9WgXcQ",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ":            "dQw4w9WgXcQ",
+		"https://www.youtube.com/shorts/dQw4w9WgXcQ":           "dQw4w9WgXcQ",
+		"https://www.youtube.com/channel/UC123":                "",
+		"https://example.com/watch?v=dQw4w9WgXcQ":              "",
+		"http://localhost:8081/files/65f1c0a2b3d4e5f6a7b8c9d0": "",
+	}
+	for input, want := range tests {
+		if got := YouTubeVideoID(input); got != want {
+			t.Errorf("YouTubeVideoID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
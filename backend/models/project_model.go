@@ -5,9 +5,26 @@ import (
 	"time"
 )
 
+// Category is addressed in URLs by Slug, which stays fixed when NameCategory is
+// renamed. Slugs replaced by a rename are kept in PreviousSlugs to redirect old links.
+// ParentID is nil for top-level categories. The remaining fields drive the service
+// landing page on the user site; hidden categories are left out of public listings.
 type Category struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty"`
-	NameCategory string             `bson:"nameCategory"`
+	ID             primitive.ObjectID             `bson:"_id,omitempty"`
+	NameCategory   string                         `bson:"nameCategory"`
+	Slug           string                         `bson:"slug"`
+	PreviousSlugs  []string                       `bson:"previousSlugs,omitempty"`
+	ParentID       *primitive.ObjectID            `bson:"parentId,omitempty"`
+	Description    string                         `bson:"description,omitempty"`
+	HeroMediaUrl   string                         `bson:"heroMediaUrl,omitempty"`
+	CoverImageUrl  string                         `bson:"coverImageUrl,omitempty"`
+	Icon           string                         `bson:"icon,omitempty"`
+	SeoTitle       string                         `bson:"seoTitle,omitempty"`
+	SeoDescription string                         `bson:"seoDescription,omitempty"`
+	DisplayOrder   int                            `bson:"displayOrder"`
+	Visible        bool                           `bson:"visible"`
+	Translations   map[string]CategoryTranslation `bson:"translations,omitempty"`
+	Version        int64                          `bson:"version"`
 }
 
 type Subtitle struct {
@@ -15,21 +32,39 @@ type Subtitle struct {
 	Headings []string `bson:"headings" json:"headings"`
 }
 
+// ServiceStep stores its content as Sections. Subtitles and Headings are a flat view
+// of the sections filled in by FlattenSections for older clients and are never stored.
 type ServiceStep struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	CategoryID primitive.ObjectID `bson:"category_id" json:"category_id"`
-	Title      string             `bson:"title" json:"title"`
-	Subtitles  []string           `bson:"subtitles" json:"subtitles"`
-	Headings   []string           `bson:"headings" json:"headings"`
-	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt  *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	ID           primitive.ObjectID                `bson:"_id,omitempty" json:"_id,omitempty"`
+	CategoryID   primitive.ObjectID                `bson:"category_id" json:"category_id"`
+	Title        string                            `bson:"title" json:"title"`
+	Sections     []Subtitle                        `bson:"sections" json:"sections"`
+	Subtitles    []string                          `bson:"-" json:"subtitles"`
+	Headings     []string                          `bson:"-" json:"headings"`
+	StepNumber   int                               `bson:"stepNumber" json:"stepNumber"`
+	Translations map[string]ServiceStepTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	CreatedAt    time.Time                         `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    *time.Time                        `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version      int64                             `bson:"version" json:"version"`
 }
 
+// Project is addressed by ID or by Slug, which is unique within its category.
+// Unpublished projects are only shown to signed-in admins.
 type Project struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty"`
-	ImageUrl   string             `bson:"imageUrl,omitempty"`
-	VideoUrl   string             `bson:"videoUrl,omitempty"`
-	CategoryID primitive.ObjectID `bson:"category_id"`
-	CreatedAt  time.Time          `bson:"createdAt"`
-	UpdatedAt  time.Time          `bson:"updatedAt,omitempty"`
+	ID           primitive.ObjectID            `bson:"_id,omitempty"`
+	Slug         string                        `bson:"slug,omitempty"`
+	Title        string                        `bson:"title,omitempty"`
+	Description  string                        `bson:"description,omitempty"`
+	Translations map[string]ProjectTranslation `bson:"translations,omitempty"`
+	ImageUrl     string                        `bson:"imageUrl,omitempty"`
+	VideoUrl     string                        `bson:"videoUrl,omitempty"`
+	CategoryID   primitive.ObjectID            `bson:"category_id"`
+	CreatedAt    time.Time                     `bson:"createdAt"`
+	UpdatedAt    time.Time                     `bson:"updatedAt,omitempty"`
+	Version      int64                         `bson:"version"`
+	Position     int                           `bson:"position"`
+	Pinned       bool                          `bson:"pinned"`
+	Published    bool                          `bson:"published"`
+	Tags         []string                      `bson:"tags,omitempty"`
+	CopiedFrom   *primitive.ObjectID           `bson:"copiedFrom,omitempty"`
 }
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Quote request statuses. A request starts as new, is reviewed, gets a priced quote and
+// is then accepted or declined by the client; any open request can be closed.
+const (
+	QuoteStatusNew       = "new"
+	QuoteStatusReviewing = "reviewing"
+	QuoteStatusQuoted    = "quoted"
+	QuoteStatusAccepted  = "accepted"
+	QuoteStatusDeclined  = "declined"
+	QuoteStatusClosed    = "closed"
+)
+
+const (
+	MaxQuoteItems       = 30
+	MaxQuoteAttachments = 3
+)
+
+var quoteTransitions = map[string][]string{
+	QuoteStatusNew:       {QuoteStatusReviewing, QuoteStatusClosed},
+	QuoteStatusReviewing: {QuoteStatusQuoted, QuoteStatusClosed},
+	// A quoted request goes back to reviewing when the quote has to be revised
+	QuoteStatusQuoted:   {QuoteStatusAccepted, QuoteStatusDeclined, QuoteStatusReviewing, QuoteStatusClosed},
+	QuoteStatusAccepted: {QuoteStatusClosed},
+	QuoteStatusDeclined: {QuoteStatusReviewing, QuoteStatusClosed},
+}
+
+// QuoteRequest is a client's request for a price on service steps of one category.
+type QuoteRequest struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name          string              `bson:"name" json:"name"`
+	Email         string              `bson:"email" json:"email"`
+	Phone         string              `bson:"phone,omitempty" json:"phone,omitempty"`
+	CategoryID    primitive.ObjectID  `bson:"category_id" json:"categoryId"`
+	Items         []QuoteItem         `bson:"items" json:"items"`
+	Message       string              `bson:"message,omitempty" json:"message,omitempty"`
+	Attachments   []QuoteFile         `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Status        string              `bson:"status" json:"status"`
+	StatusHistory []QuoteStatusChange `bson:"statusHistory,omitempty" json:"statusHistory,omitempty"`
+	Quote         *QuoteDocument      `bson:"quote,omitempty" json:"quote,omitempty"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     *time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version       int64               `bson:"version" json:"version"`
+}
+
+// QuoteItem is a selected service step with the chosen options, which are texts of the
+// step's sections. Title is copied so the request still reads the same if the step changes.
+type QuoteItem struct {
+	StepID  primitive.ObjectID `bson:"step_id" json:"stepId"`
+	Title   string             `bson:"title" json:"title"`
+	Options []string           `bson:"options,omitempty" json:"options,omitempty"`
+}
+
+// QuoteFile is an uploaded file kept with a quote request.
+type QuoteFile struct {
+	FileID   primitive.ObjectID `bson:"file_id" json:"fileId"`
+	Filename string             `bson:"filename" json:"filename"`
+	Size     int64              `bson:"size" json:"size"`
+}
+
+// QuoteDocument is the priced quote an admin sends back.
+type QuoteDocument struct {
+	File       QuoteFile  `bson:"file" json:"file"`
+	Amount     float64    `bson:"amount" json:"amount"`
+	Currency   string     `bson:"currency" json:"currency"`
+	ValidUntil *time.Time `bson:"validUntil,omitempty" json:"validUntil,omitempty"`
+	Note       string     `bson:"note,omitempty" json:"note,omitempty"`
+	SentBy     string     `bson:"sentBy" json:"sentBy"`
+	SentAt     time.Time  `bson:"sentAt" json:"sentAt"`
+}
+
+type QuoteStatusChange struct {
+	From      string    `bson:"from" json:"from"`
+	To        string    `bson:"to" json:"to"`
+	By        string    `bson:"by" json:"by"`
+	Note      string    `bson:"note,omitempty" json:"note,omitempty"`
+	ChangedAt time.Time `bson:"changedAt" json:"changedAt"`
+}
+
+// IsQuoteStatus reports whether status is one of the known quote request statuses.
+func IsQuoteStatus(status string) bool {
+	if status == QuoteStatusClosed {
+		return true
+	}
+	_, ok := quoteTransitions[status]
+	return ok
+}
+
+// CanTransitionQuote reports whether a quote request may move from one status to another.
+func CanTransitionQuote(from, to string) bool {
+	for _, next := range quoteTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
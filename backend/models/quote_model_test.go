@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestCanTransitionQuote(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected bool
+	}{
+		{QuoteStatusNew, QuoteStatusReviewing, true},
+		{QuoteStatusNew, QuoteStatusQuoted, false},
+		{QuoteStatusReviewing, QuoteStatusQuoted, true},
+		{QuoteStatusQuoted, QuoteStatusAccepted, true},
+		{QuoteStatusQuoted, QuoteStatusReviewing, true},
+		{QuoteStatusAccepted, QuoteStatusDeclined, false},
+		{QuoteStatusClosed, QuoteStatusNew, false},
+		{QuoteStatusNew, QuoteStatusNew, false},
+	}
+	for _, test := range tests {
+		if got := CanTransitionQuote(test.from, test.to); got != test.expected {
+			t.Errorf("CanTransitionQuote(%q, %q) = %v; want %v", test.from, test.to, got, test.expected)
+		}
+	}
+}
+
+func TestIsQuoteStatus(t *testing.T) {
+	for _, status := range []string{"new", "reviewing", "quoted", "accepted", "declined", "closed"} {
+		if !IsQuoteStatus(status) {
+			t.Errorf("IsQuoteStatus(%q) = false; want true", status)
+		}
+	}
+	if IsQuoteStatus("New") {
+		t.Errorf("IsQuoteStatus(%q) = true; want false", "New")
+	}
+}
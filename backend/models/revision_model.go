@@ -0,0 +1,61 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RevisionEntityProject      = "project"
+	RevisionEntityServiceStep  = "serviceStep"
+	RevisionEntitySiteSettings = "siteSettings"
+)
+
+// Revision is an immutable full snapshot of an entity taken after each change.
+type Revision struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	EntityType   string              `bson:"entityType" json:"entityType"`
+	EntityID     primitive.ObjectID  `bson:"entityId" json:"entityId"`
+	Number       int                 `bson:"number" json:"number"`
+	Snapshot     bson.M              `bson:"snapshot" json:"snapshot"`
+	AuthorEmail  string              `bson:"authorEmail" json:"authorEmail"`
+	RestoredFrom *primitive.ObjectID `bson:"restoredFrom,omitempty" json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffSnapshots returns the top-level fields that differ between two snapshots, sorted by field name.
+func DiffSnapshots(from, to bson.M) []FieldChange {
+	keys := map[string]struct{}{}
+	for k := range from {
+		keys[k] = struct{}{}
+	}
+	for k := range to {
+		keys[k] = struct{}{}
+	}
+
+	fields := make([]string, 0, len(keys))
+	for k := range keys {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	changes := []FieldChange{}
+	for _, field := range fields {
+		a, b := from[field], to[field]
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, From: a, To: b})
+	}
+	return changes
+}
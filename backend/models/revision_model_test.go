@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	from := bson.M{
+		"title":     "Logo",
+		"subtitles": bson.A{"a", "b"},
+		"imageUrl":  "http://x/files/1",
+	}
+	to := bson.M{
+		"title":     "Logo",
+		"subtitles": bson.A{"a", "c"},
+		"videoUrl":  "http://x/files/2",
+	}
+
+	changes := DiffSnapshots(from, to)
+	if len(changes) != 3 {
+		t.Fatalf("DiffSnapshots returned %d changes; want 3: %+v", len(changes), changes)
+	}
+
+	want := []string{"imageUrl", "subtitles", "videoUrl"}
+	for i, field := range want {
+		if changes[i].Field != field {
+			t.Errorf("changes[%d].Field = %q; want %q", i, changes[i].Field, field)
+		}
+	}
+
+	if changes[0].To != nil {
+		t.Errorf("removed field imageUrl should have nil To, got %v", changes[0].To)
+	}
+
+	if len(DiffSnapshots(from, from)) != 0 {
+		t.Errorf("DiffSnapshots of identical snapshots should be empty")
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Search result types, also accepted by the type filter of the search endpoint.
+const (
+	SearchTypeProject     = "project"
+	SearchTypeCategory    = "category"
+	SearchTypeServiceStep = "serviceStep"
+)
+
+// ContainsThai reports whether s has Thai script. MongoDB text indexes split words on
+// spaces and punctuation, which Thai does not use between words, so such queries are
+// matched as substrings instead.
+func ContainsThai(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Thai, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchTerms splits a query into lowercase terms, dropping the quote and negation
+// characters of the $text syntax and duplicate terms.
+func SearchTerms(q string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, field := range strings.Fields(NormalizeText(q)) {
+		term := strings.ToLower(strings.Trim(field, "\"-"))
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// findTerm returns the rune index of the first case-insensitive occurrence of any term
+// in text and the matched length, or -1.
+func findTerm(text []rune, terms [][]rune, from int) (int, int) {
+	for i := from; i < len(text); i++ {
+		for _, term := range terms {
+			if len(term) == 0 || i+len(term) > len(text) {
+				continue
+			}
+			match := true
+			for j, r := range term {
+				if unicode.ToLower(text[i+j]) != r {
+					match = false
+					break
+				}
+			}
+			if match {
+				return i, len(term)
+			}
+		}
+	}
+	return -1, 0
+}
+
+// HighlightSnippet cuts a window of about radius characters on each side of the first
+// matching term and wraps every match in <mark>. The rest of the text is HTML-escaped,
+// so the result can be rendered as HTML. ok is false when no term occurs in text.
+func HighlightSnippet(text string, terms []string, radius int) (snippet string, ok bool) {
+	runes := []rune(text)
+	lowered := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		lowered = append(lowered, []rune(strings.ToLower(term)))
+	}
+
+	first, n := findTerm(runes, lowered, 0)
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := first-radius, first+n+radius
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+	// Never cut between a Thai consonant and its vowel or tone marks
+	for start > 0 && unicode.Is(unicode.Mn, runes[start]) {
+		start--
+	}
+	for end < len(runes) && unicode.Is(unicode.Mn, runes[end]) {
+		end++
+	}
+	window := runes[start:end]
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := 0
+	for pos < len(window) {
+		i, n := findTerm(window, lowered, pos)
+		if i < 0 {
+			b.WriteString(html.EscapeString(string(window[pos:])))
+			break
+		}
+		b.WriteString(html.EscapeString(string(window[pos:i])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(window[i : i+n])))
+		b.WriteString("</mark>")
+		pos = i + n
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// TruncateText shortens text to at most max characters for snippets without a match.
+func TruncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return html.EscapeString(text)
+	}
+	return html.EscapeString(string(runes[:max])) + "…"
+}
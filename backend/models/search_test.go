@@ -0,0 +1,55 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainsThai(t *testing.T) {
+	if !ContainsThai("ออกแบบโลโก้") {
+		t.Error("Thai text not detected")
+	}
+	if !ContainsThai("logo ร้านอาหาร") {
+		t.Error("mixed text not detected")
+	}
+	if ContainsThai("Logo design") {
+		t.Error("Latin text detected as Thai")
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms(`  Logo "Restaurant"  -draft logo `)
+	want := []string{"logo", "restaurant", "draft"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	snippet, ok := HighlightSnippet("A <b>bold</b> Logo for a restaurant", []string{"logo"}, 100)
+	if !ok {
+		t.Fatal("match not found")
+	}
+	want := "A &lt;b&gt;bold&lt;/b&gt; <mark>Logo</mark> for a restaurant"
+	if snippet != want {
+		t.Fatalf("got %q, want %q", snippet, want)
+	}
+
+	snippet, ok = HighlightSnippet("บริการออกแบบโลโก้สำหรับร้านอาหารและคาเฟ่", []string{"โลโก้"}, 6)
+	if !ok {
+		t.Fatal("Thai match not found")
+	}
+	if want := "…ออกแบบ<mark>โลโก้</mark>สำหรับ…"; snippet != want {
+		t.Fatalf("got %q, want %q", snippet, want)
+	}
+
+	// The window is widened rather than splitting "ที่" from its vowel and tone mark
+	snippet, _ = HighlightSnippet("ร้านที่ดีที่สุด", []string{"ดี"}, 1)
+	if want := "…ที่<mark>ดี</mark>ที่…"; snippet != want {
+		t.Fatalf("got %q, want %q", snippet, want)
+	}
+
+	if _, ok := HighlightSnippet("Website", []string{"logo"}, 10); ok {
+		t.Fatal("unexpected match")
+	}
+}
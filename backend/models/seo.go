@@ -0,0 +1,226 @@
+package models
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SiteName is the studio name shown in feed titles and link previews.
+const SiteName = "DsignMe"
+
+const (
+	// MaxCardDescriptionLength keeps link preview descriptions to what Facebook, LINE and
+	// X show before cutting them off, counted in characters.
+	MaxCardDescriptionLength = 200
+	// FeedEntryLimit is the number of newest projects listed in a category feed.
+	FeedEntryLimit = 20
+)
+
+// StaticSitePaths are the pages of the user site that do not come from content.
+var StaticSitePaths = []string{"/", "/about", "/contact"}
+
+// CategoryPagePath is the user site page of a category; service pages live under /services.
+func CategoryPagePath(categorySlug string) string {
+	return "/services/" + url.PathEscape(categorySlug)
+}
+
+// ProjectPagePath opens the project on its category page. Projects have no page of their
+// own on the user site, the ?project= parameter opens its dialog.
+func ProjectPagePath(categorySlug string, project Project) string {
+	ref := project.Slug
+	if ref == "" {
+		ref = project.ID.Hex()
+	}
+	return CategoryPagePath(categorySlug) + "?project=" + url.QueryEscape(ref)
+}
+
+// AbsoluteURL resolves a site path such as /files/... against base. Absolute URLs and
+// empty values are returned as they are.
+func AbsoluteURL(base, u string) string {
+	if strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+		return strings.TrimRight(base, "/") + u
+	}
+	return u
+}
+
+// LastModified is when the project last changed, for sitemaps and feeds.
+func (p Project) LastModified() time.Time {
+	if p.UpdatedAt.After(p.CreatedAt) {
+		return p.UpdatedAt
+	}
+	return p.CreatedAt
+}
+
+// SitemapURL is one <url> entry of a sitemap. LastMod is a W3C date and may be empty.
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapDate formats t for <lastmod>, or returns "" for the zero time.
+func SitemapDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+// BuildSitemap renders urls as a sitemaps.org document.
+func BuildSitemap(urls []SitemapURL) ([]byte, error) {
+	body, err := xml.MarshalIndent(sitemapURLSet{URLs: urls}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// AtomFeed is an Atom 1.0 feed. Times are RFC 3339 strings, see AtomDate.
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr,omitempty"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomPerson  `xml:"author"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Published string     `xml:"published,omitempty"`
+	Updated   string     `xml:"updated"`
+	Links     []AtomLink `xml:"link"`
+	Summary   string     `xml:"summary,omitempty"`
+}
+
+// AtomDate formats t as an Atom date.
+func AtomDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// AtomID builds a tag URI (RFC 4151) for an entity. Unlike page URLs it stays the same
+// when the entity or its category is renamed, so feed readers do not show it twice.
+func AtomID(siteURL, specific string) string {
+	host := siteURL
+	if u, err := url.Parse(siteURL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return "tag:" + host + ",2024:" + specific
+}
+
+// BuildAtomFeed renders the feed as XML.
+func BuildAtomFeed(feed AtomFeed) ([]byte, error) {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// CardMeta is one <meta> tag of a link preview. Open Graph tags use property, Twitter
+// card tags use name.
+type CardMeta struct {
+	Property string `json:"property,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Content  string `json:"content"`
+}
+
+// LinkCard describes how a page previews when shared: Open Graph for Facebook and LINE,
+// Twitter card for X. Meta lists the same values as tags ready to inject into <head>.
+type LinkCard struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	Image       string     `json:"image,omitempty"`
+	ImageAlt    string     `json:"imageAlt,omitempty"`
+	Type        string     `json:"type"`
+	SiteName    string     `json:"siteName"`
+	Locale      string     `json:"locale"`
+	TwitterCard string     `json:"twitterCard"`
+	Meta        []CardMeta `json:"meta"`
+}
+
+// openGraphLocales maps content locales to the territory form Open Graph expects.
+var openGraphLocales = map[string]string{"th": "th_TH", "en": "en_US"}
+
+// NewLinkCard fills in the card for a page and builds its meta tags. Pages with an image
+// get the large image card.
+func NewLinkCard(cardType, title, description, pageURL, image, locale string) LinkCard {
+	card := LinkCard{
+		Title:       title,
+		Description: CardDescription(description),
+		URL:         pageURL,
+		Image:       image,
+		Type:        cardType,
+		SiteName:    SiteName,
+		Locale:      openGraphLocales[locale],
+		TwitterCard: "summary",
+	}
+	if card.Locale == "" {
+		card.Locale = openGraphLocales[DefaultLocale]
+	}
+	if image != "" {
+		card.ImageAlt = title
+		card.TwitterCard = "summary_large_image"
+	}
+
+	card.Meta = []CardMeta{
+		{Property: "og:type", Content: card.Type},
+		{Property: "og:site_name", Content: card.SiteName},
+		{Property: "og:locale", Content: card.Locale},
+		{Property: "og:title", Content: card.Title},
+		{Property: "og:description", Content: card.Description},
+		{Property: "og:url", Content: card.URL},
+	}
+	if image != "" {
+		card.Meta = append(card.Meta,
+			CardMeta{Property: "og:image", Content: card.Image},
+			CardMeta{Property: "og:image:alt", Content: card.ImageAlt},
+		)
+	}
+	card.Meta = append(card.Meta,
+		CardMeta{Name: "twitter:card", Content: card.TwitterCard},
+		CardMeta{Name: "twitter:title", Content: card.Title},
+		CardMeta{Name: "twitter:description", Content: card.Description},
+	)
+	if image != "" {
+		card.Meta = append(card.Meta, CardMeta{Name: "twitter:image", Content: card.Image})
+	}
+	return card
+}
+
+// CardDescription flattens text to one line and shortens it to MaxCardDescriptionLength
+// characters, preferring to cut at a space. Thai is written without spaces between
+// words, so Thai text may be cut mid-sentence.
+func CardDescription(text string) string {
+	text = NormalizeText(text)
+	if utf8.RuneCountInString(text) <= MaxCardDescriptionLength {
+		return text
+	}
+	runes := []rune(text)[:MaxCardDescriptionLength-1]
+	cut := string(runes)
+	if i := strings.LastIndex(cut, " "); i > len(cut)*3/4 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
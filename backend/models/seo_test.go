@@ -0,0 +1,116 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPagePaths(t *testing.T) {
+	if got := CategoryPagePath("website-develop"); got != "/services/website-develop" {
+		t.Errorf("CategoryPagePath = %q", got)
+	}
+	id, _ := primitive.ObjectIDFromHex("65f1c0a2b3d4e5f6a7b8c9d0")
+	if got := ProjectPagePath("logo", Project{ID: id, Slug: "coffee-shop"}); got != "/services/logo?project=coffee-shop" {
+		t.Errorf("ProjectPagePath = %q", got)
+	}
+	if got := ProjectPagePath("logo", Project{ID: id}); got != "/services/logo?project=65f1c0a2b3d4e5f6a7b8c9d0" {
+		t.Errorf("ProjectPagePath without slug = %q", got)
+	}
+	if got := AbsoluteURL("https://dsignme.com/", "/files/1"); got != "https://dsignme.com/files/1" {
+		t.Errorf("AbsoluteURL = %q", got)
+	}
+	if got := AbsoluteURL("https://dsignme.com", "https://cdn.example.com/a.png"); got != "https://cdn.example.com/a.png" {
+		t.Errorf("AbsoluteURL kept = %q", got)
+	}
+}
+
+func TestBuildSitemap(t *testing.T) {
+	body, err := BuildSitemap([]SitemapURL{
+		{Loc: "https://dsignme.com/"},
+		{Loc: "https://dsignme.com/services/logo?project=a&b", LastMod: SitemapDate(time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))},
+	})
+	if err != nil {
+		t.Fatalf("BuildSitemap: %v", err)
+	}
+	xml := string(body)
+	for _, part := range []string{
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		"<loc>https://dsignme.com/</loc>\n  </url>",
+		"<loc>https://dsignme.com/services/logo?project=a&amp;b</loc>",
+		"<lastmod>2024-05-01</lastmod>",
+	} {
+		if !strings.Contains(xml, part) {
+			t.Errorf("sitemap %s does not contain %q", xml, part)
+		}
+	}
+}
+
+func TestBuildAtomFeed(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)
+	body, err := BuildAtomFeed(AtomFeed{
+		Lang:    "th",
+		ID:      AtomID("https://dsignme.com", "category:logo"),
+		Title:   "โลโก้ - DsignMe",
+		Updated: AtomDate(updated),
+		Author:  AtomPerson{Name: SiteName},
+		Links:   []AtomLink{{Href: "https://dsignme.com/services/logo", Rel: "alternate", Type: "text/html"}},
+		Entries: []AtomEntry{{ID: "tag:dsignme.com,2024:project:1", Title: "ร้านกาแฟ", Updated: AtomDate(updated)}},
+	})
+	if err != nil {
+		t.Fatalf("BuildAtomFeed: %v", err)
+	}
+	xml := string(body)
+	for _, part := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="th">`,
+		"<id>tag:dsignme.com,2024:category:logo</id>",
+		"<updated>2024-05-01T03:30:00Z</updated>",
+		`<link href="https://dsignme.com/services/logo" rel="alternate" type="text/html"></link>`,
+		"<title>ร้านกาแฟ</title>",
+	} {
+		if !strings.Contains(xml, part) {
+			t.Errorf("feed %s does not contain %q", xml, part)
+		}
+	}
+}
+
+func TestNewLinkCard(t *testing.T) {
+	card := NewLinkCard("article", "ร้านกาแฟ", "โลโก้\nร้านกาแฟ", "https://dsignme.com/services/logo?project=cafe", "https://api.dsignme.com/files/1", "en")
+	if card.Description != "โลโก้ ร้านกาแฟ" || card.Locale != "en_US" || card.TwitterCard != "summary_large_image" {
+		t.Errorf("card = %+v", card)
+	}
+	meta := map[string]string{}
+	for _, tag := range card.Meta {
+		meta[tag.Property+tag.Name] = tag.Content
+	}
+	if meta["og:image"] != card.Image || meta["twitter:image"] != card.Image || meta["og:title"] != "ร้านกาแฟ" {
+		t.Errorf("meta = %v", meta)
+	}
+
+	card = NewLinkCard("website", "Logo", "", "https://dsignme.com/services/logo", "", "de")
+	if card.TwitterCard != "summary" || card.Locale != "th_TH" {
+		t.Errorf("card without image = %+v", card)
+	}
+	for _, tag := range card.Meta {
+		if tag.Property == "og:image" || tag.Name == "twitter:image" {
+			t.Errorf("card without image has %+v", tag)
+		}
+	}
+}
+
+func TestCardDescription(t *testing.T) {
+	long := strings.Repeat("design ", 40)
+	got := CardDescription(long)
+	if utf8.RuneCountInString(got) > MaxCardDescriptionLength || !strings.HasSuffix(got, "design…") {
+		t.Errorf("CardDescription(long) = %q", got)
+	}
+
+	thai := strings.Repeat("ออกแบบ", 50)
+	got = CardDescription(thai)
+	if utf8.RuneCountInString(got) != MaxCardDescriptionLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("CardDescription(thai) has %d characters", utf8.RuneCountInString(got))
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "strings"
+
+// NormalizeSections trims the text of every section, drops empty headings and
+// removes sections that end up with neither text nor headings.
+func NormalizeSections(sections []Subtitle) []Subtitle {
+	normalized := []Subtitle{}
+	for _, section := range sections {
+		text := strings.TrimSpace(section.Text)
+		headings := []string{}
+		for _, heading := range section.Headings {
+			if h := strings.TrimSpace(heading); h != "" {
+				headings = append(headings, h)
+			}
+		}
+		if text == "" && len(headings) == 0 {
+			continue
+		}
+		normalized = append(normalized, Subtitle{Text: text, Headings: headings})
+	}
+	return normalized
+}
+
+// SectionsFromFlat converts the legacy flat subtitles/headings pair into sections.
+// The flat format never recorded which subtitle a heading belonged to; the public
+// pages render headings as a list after the subtitles, so they are attached to the
+// last subtitle. Headings without any subtitle form a single untitled section.
+func SectionsFromFlat(subtitles, headings []string) []Subtitle {
+	sections := []Subtitle{}
+	for _, subtitle := range subtitles {
+		sections = append(sections, Subtitle{Text: subtitle, Headings: []string{}})
+	}
+	if len(headings) > 0 {
+		if len(sections) == 0 {
+			sections = append(sections, Subtitle{})
+		}
+		last := &sections[len(sections)-1]
+		last.Headings = append(last.Headings, headings...)
+	}
+	return NormalizeSections(sections)
+}
+
+// FlattenSections fills Subtitles and Headings from Sections for clients that
+// still read the flat format.
+func (s *ServiceStep) FlattenSections() {
+	s.Subtitles = []string{}
+	s.Headings = []string{}
+	for _, section := range s.Sections {
+		if section.Text != "" {
+			s.Subtitles = append(s.Subtitles, section.Text)
+		}
+		s.Headings = append(s.Headings, section.Headings...)
+	}
+	if s.Sections == nil {
+		s.Sections = []Subtitle{}
+	}
+}
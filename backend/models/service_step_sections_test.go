@@ -0,0 +1,57 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSectionsFromFlat(t *testing.T) {
+	tests := []struct {
+		name      string
+		subtitles []string
+		headings  []string
+		expected  []Subtitle
+	}{
+		{
+			name:      "headings attach to last subtitle",
+			subtitles: []string{"Brief", "Design"},
+			headings:  []string{"Moodboard", "Sketch"},
+			expected: []Subtitle{
+				{Text: "Brief", Headings: []string{}},
+				{Text: "Design", Headings: []string{"Moodboard", "Sketch"}},
+			},
+		},
+		{
+			name:     "headings without subtitle",
+			headings: []string{"Logo", ""},
+			expected: []Subtitle{{Text: "", Headings: []string{"Logo"}}},
+		},
+		{
+			name:      "empty values are dropped",
+			subtitles: []string{"", "  "},
+			expected:  []Subtitle{},
+		},
+	}
+
+	for _, test := range tests {
+		result := SectionsFromFlat(test.subtitles, test.headings)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: SectionsFromFlat = %+v; want %+v", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestFlattenSections(t *testing.T) {
+	step := &ServiceStep{Sections: []Subtitle{
+		{Text: "ระยะเวลา 7 วัน", Headings: []string{"A"}},
+		{Text: "", Headings: []string{"B", "C"}},
+	}}
+	step.FlattenSections()
+
+	if !reflect.DeepEqual(step.Subtitles, []string{"ระยะเวลา 7 วัน"}) {
+		t.Errorf("Subtitles = %v", step.Subtitles)
+	}
+	if !reflect.DeepEqual(step.Headings, []string{"A", "B", "C"}) {
+		t.Errorf("Headings = %v", step.Headings)
+	}
+}
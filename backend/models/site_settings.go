@@ -0,0 +1,353 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SiteSettingsKey identifies the single site settings document.
+const SiteSettingsKey = "site"
+
+// Contact channel types. Email and phone channels are linked with mailto: and tel: by
+// the site; LINE channels carry their add-friend link and QR code.
+const (
+	ContactChannelEmail = "email"
+	ContactChannelPhone = "phone"
+	ContactChannelLine  = "line"
+)
+
+// SocialPlatforms are the networks the site has icons for.
+var SocialPlatforms = []string{"facebook", "instagram", "line", "tiktok", "youtube", "x"}
+
+// Weekdays name the days of BusinessHours, Monday first.
+var Weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+const (
+	MaxSettingsItems       = 20
+	MaxHeroSlides          = 10
+	MaxSettingsLabelLength = 100
+	MaxSettingsTextLength  = 1000
+)
+
+var settingsTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+type ContactChannel struct {
+	Type       string `bson:"type" json:"type"`
+	Label      string `bson:"label,omitempty" json:"label,omitempty"`
+	Value      string `bson:"value" json:"value"`
+	Url        string `bson:"url,omitempty" json:"url,omitempty"`
+	QrImageUrl string `bson:"qrImageUrl,omitempty" json:"qrImageUrl,omitempty"`
+}
+
+type SocialLink struct {
+	Platform string `bson:"platform" json:"platform"`
+	Label    string `bson:"label,omitempty" json:"label,omitempty"`
+	Url      string `bson:"url" json:"url"`
+}
+
+// BusinessHours are the opening hours of one day as HH:MM in Bangkok time.
+type BusinessHours struct {
+	Day    string `bson:"day" json:"day"`
+	Open   string `bson:"open,omitempty" json:"open,omitempty"`
+	Close  string `bson:"close,omitempty" json:"close,omitempty"`
+	Closed bool   `bson:"closed" json:"closed"`
+}
+
+// HeroSlide is one slide of the home page hero. The button is optional.
+type HeroSlide struct {
+	Title       string `bson:"title" json:"title"`
+	Subtitle    string `bson:"subtitle,omitempty" json:"subtitle,omitempty"`
+	Text        string `bson:"text,omitempty" json:"text,omitempty"`
+	ImageUrl    string `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
+	ButtonLabel string `bson:"buttonLabel,omitempty" json:"buttonLabel,omitempty"`
+	ButtonUrl   string `bson:"buttonUrl,omitempty" json:"buttonUrl,omitempty"`
+}
+
+// SeoDefaults are used by pages that have no SEO fields of their own.
+type SeoDefaults struct {
+	Title       string `bson:"title" json:"title"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	ImageUrl    string `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
+}
+
+// SiteSettings holds the contact details and brand content of the user site, so they can
+// be changed from the admin without a deploy. There is a single document, found by Key.
+type SiteSettings struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Key           string             `bson:"key" json:"-"`
+	Contacts      []ContactChannel   `bson:"contacts" json:"contacts"`
+	Social        []SocialLink       `bson:"social" json:"social"`
+	BusinessHours []BusinessHours    `bson:"businessHours" json:"businessHours"`
+	HoursNote     string             `bson:"hoursNote,omitempty" json:"hoursNote,omitempty"`
+	HeroSlides    []HeroSlide        `bson:"heroSlides" json:"heroSlides"`
+	FooterText    string             `bson:"footerText,omitempty" json:"footerText,omitempty"`
+	Seo           SeoDefaults        `bson:"seo" json:"seo"`
+	UpdatedAt     *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version       int64              `bson:"version" json:"version"`
+}
+
+// DefaultSiteSettings is what the site showed before settings were editable. It is served
+// until the settings are saved for the first time.
+func DefaultSiteSettings() SiteSettings {
+	return SiteSettings{
+		Key: SiteSettingsKey,
+		Contacts: []ContactChannel{
+			{
+				Type:       ContactChannelLine,
+				Label:      "@DsignMe",
+				Value:      "@DsignMe",
+				Url:        "https://lin.ee/c2HxJS1",
+				QrImageUrl: "https://qr-official.line.me/gs/M_472lvzyx_BW.png?oat_content=qr",
+			},
+			{Type: ContactChannelEmail, Value: "dsignme18@gmail.com"},
+		},
+		Social: []SocialLink{
+			{Platform: "line", Label: "Line", Url: "https://lin.ee/c2HxJS1"},
+			{Platform: "instagram", Label: "DsignMe", Url: "https://www.instagram.com/dee_signme?utm_source=ig_web_button_share_sheet&igsh=ZDNlZDc0MzIxNw=="},
+			{Platform: "facebook", Label: "DesignMe", Url: "https://www.facebook.com/profile.php?id=61561907601448&mibextid=ZbWKwL"},
+		},
+		BusinessHours: []BusinessHours{},
+		HeroSlides: []HeroSlide{
+			{
+				Title:    "Design Your Unique Identity",
+				Subtitle: "ออกแบบอัตลักษณ์อย่างมี\"เอกลักษณ์\"",
+				Text: "พวกเราคือกราฟิกดีไซน์เนอร์ที่เชี่ยวชาญด้านการออกแบบ\nมีประสบการณ์มากกว่า 4 ปี\n" +
+					"ที่สามารถช่วยส่งเสริมธุรกิจด้วยการออกแบบที่ดีที่สุดให้กับธุรกิจคุณ",
+				ButtonLabel: "แอดไลน์เราเพื่อปรึกษาฟรี",
+				ButtonUrl:   "https://lin.ee/nrIGpEa",
+			},
+		},
+		FooterText: "พวกเราคือกราฟิกดีไซน์เนอร์ เชี่ยวชาญด้านการออกแบบ ที่มีประสบการณ์มากกว่า 4 ปี " +
+			"ที่สามารถช่วยส่งเสริมธุรกิจด้วยการออกแบบที่ดีที่สุดให้กับธุรกิจคุณ",
+		Seo: SeoDefaults{
+			Title:       SiteName,
+			Description: "กราฟิกดีไซน์เนอร์ผู้เชี่ยวชาญด้านการออกแบบโลโก้ สื่อโฆษณา บรรจุภัณฑ์ และเว็บไซต์ ประสบการณ์มากกว่า 4 ปี",
+		},
+	}
+}
+
+// Normalize trims the text fields and lower-cases the enumerations, so "Email" and
+// " email " are accepted. Missing lists become empty lists.
+func (s *SiteSettings) Normalize() {
+	if s.Contacts == nil {
+		s.Contacts = []ContactChannel{}
+	}
+	for i := range s.Contacts {
+		contact := &s.Contacts[i]
+		contact.Type = strings.ToLower(strings.TrimSpace(contact.Type))
+		contact.Label = NormalizeText(contact.Label)
+		contact.Value = NormalizeText(contact.Value)
+		if contact.Type == ContactChannelEmail {
+			contact.Value = strings.ToLower(contact.Value)
+		}
+		contact.Url = strings.TrimSpace(contact.Url)
+		contact.QrImageUrl = strings.TrimSpace(contact.QrImageUrl)
+	}
+
+	if s.Social == nil {
+		s.Social = []SocialLink{}
+	}
+	for i := range s.Social {
+		link := &s.Social[i]
+		link.Platform = strings.ToLower(strings.TrimSpace(link.Platform))
+		link.Label = NormalizeText(link.Label)
+		link.Url = strings.TrimSpace(link.Url)
+	}
+
+	if s.BusinessHours == nil {
+		s.BusinessHours = []BusinessHours{}
+	}
+	for i := range s.BusinessHours {
+		hours := &s.BusinessHours[i]
+		hours.Day = strings.ToLower(strings.TrimSpace(hours.Day))
+		if hours.Closed {
+			hours.Open, hours.Close = "", ""
+		} else {
+			hours.Open = strings.TrimSpace(hours.Open)
+			hours.Close = strings.TrimSpace(hours.Close)
+		}
+	}
+	s.HoursNote = NormalizeText(s.HoursNote)
+
+	if s.HeroSlides == nil {
+		s.HeroSlides = []HeroSlide{}
+	}
+	for i := range s.HeroSlides {
+		slide := &s.HeroSlides[i]
+		slide.Title = NormalizeText(slide.Title)
+		slide.Subtitle = NormalizeText(slide.Subtitle)
+		slide.Text = NormalizeMultiline(slide.Text)
+		slide.ImageUrl = strings.TrimSpace(slide.ImageUrl)
+		slide.ButtonLabel = NormalizeText(slide.ButtonLabel)
+		slide.ButtonUrl = strings.TrimSpace(slide.ButtonUrl)
+	}
+	s.FooterText = NormalizeMultiline(s.FooterText)
+
+	s.Seo.Title = NormalizeText(s.Seo.Title)
+	s.Seo.Description = NormalizeText(s.Seo.Description)
+	s.Seo.ImageUrl = strings.TrimSpace(s.Seo.ImageUrl)
+}
+
+// Validate checks the settings after Normalize. Errors name the list item at fault,
+// counting from 1 as the admin form shows them.
+func (s *SiteSettings) Validate() error {
+	if len(s.Contacts) > MaxSettingsItems {
+		return fmt.Errorf("At most %d contact channels are allowed", MaxSettingsItems)
+	}
+	for i, contact := range s.Contacts {
+		if err := contact.validate(); err != nil {
+			return fmt.Errorf("Contact channel %d: %v", i+1, err)
+		}
+	}
+
+	if len(s.Social) > MaxSettingsItems {
+		return fmt.Errorf("At most %d social links are allowed", MaxSettingsItems)
+	}
+	for i, link := range s.Social {
+		if !isSocialPlatform(link.Platform) {
+			return fmt.Errorf("Social link %d: platform must be one of %v", i+1, SocialPlatforms)
+		}
+		if utf8.RuneCountInString(link.Label) > MaxSettingsLabelLength {
+			return fmt.Errorf("Social link %d: label must be at most %d characters", i+1, MaxSettingsLabelLength)
+		}
+		if !isWebURL(link.Url) {
+			return fmt.Errorf("Social link %d: URL must be an http(s) URL", i+1)
+		}
+	}
+
+	seenDays := map[string]bool{}
+	for i, hours := range s.BusinessHours {
+		if !isWeekday(hours.Day) {
+			return fmt.Errorf("Business hours %d: day must be one of %v", i+1, Weekdays)
+		}
+		if seenDays[hours.Day] {
+			return fmt.Errorf("Business hours %d: %s is listed twice", i+1, hours.Day)
+		}
+		seenDays[hours.Day] = true
+		if hours.Closed {
+			continue
+		}
+		if !settingsTimePattern.MatchString(hours.Open) || !settingsTimePattern.MatchString(hours.Close) {
+			return fmt.Errorf("Business hours %d: opening and closing times must be HH:MM", i+1)
+		}
+		if hours.Open >= hours.Close {
+			return fmt.Errorf("Business hours %d: closing time must be after opening time", i+1)
+		}
+	}
+	if utf8.RuneCountInString(s.HoursNote) > MaxSettingsLabelLength {
+		return fmt.Errorf("Hours note must be at most %d characters", MaxSettingsLabelLength)
+	}
+
+	if len(s.HeroSlides) > MaxHeroSlides {
+		return fmt.Errorf("At most %d hero slides are allowed", MaxHeroSlides)
+	}
+	for i, slide := range s.HeroSlides {
+		if err := slide.validate(); err != nil {
+			return fmt.Errorf("Hero slide %d: %v", i+1, err)
+		}
+	}
+	if utf8.RuneCountInString(s.FooterText) > MaxSettingsTextLength {
+		return fmt.Errorf("Footer text must be at most %d characters", MaxSettingsTextLength)
+	}
+
+	if s.Seo.Title == "" {
+		return fmt.Errorf("SEO title is required")
+	}
+	if utf8.RuneCountInString(s.Seo.Title) > MaxSeoTitleLength {
+		return fmt.Errorf("SEO title must be at most %d characters", MaxSeoTitleLength)
+	}
+	if utf8.RuneCountInString(s.Seo.Description) > MaxSeoDescriptionLength {
+		return fmt.Errorf("SEO description must be at most %d characters", MaxSeoDescriptionLength)
+	}
+	if s.Seo.ImageUrl != "" && !IsMediaURL(s.Seo.ImageUrl) {
+		return fmt.Errorf("SEO image URL must be an http(s) URL or a site path")
+	}
+	return nil
+}
+
+func (c ContactChannel) validate() error {
+	switch c.Type {
+	case ContactChannelEmail:
+		if !(&User{Email: c.Value}).IsEmailValid() {
+			return fmt.Errorf("invalid email address")
+		}
+	case ContactChannelPhone:
+		if !contactPhonePattern.MatchString(c.Value) {
+			return fmt.Errorf("invalid phone number")
+		}
+	case ContactChannelLine:
+		if c.Value == "" {
+			return fmt.Errorf("LINE ID is required")
+		}
+		if !isWebURL(c.Url) {
+			return fmt.Errorf("LINE channels need their add-friend URL")
+		}
+	default:
+		return fmt.Errorf("type must be %s, %s or %s", ContactChannelEmail, ContactChannelPhone, ContactChannelLine)
+	}
+	if utf8.RuneCountInString(c.Label) > MaxSettingsLabelLength || utf8.RuneCountInString(c.Value) > MaxSettingsLabelLength {
+		return fmt.Errorf("label and value must be at most %d characters", MaxSettingsLabelLength)
+	}
+	if c.Url != "" && !isWebURL(c.Url) {
+		return fmt.Errorf("URL must be an http(s) URL")
+	}
+	if c.QrImageUrl != "" && !IsMediaURL(c.QrImageUrl) {
+		return fmt.Errorf("QR image URL must be an http(s) URL or a site path")
+	}
+	return nil
+}
+
+func (s HeroSlide) validate() error {
+	if s.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if utf8.RuneCountInString(s.Title) > MaxSettingsLabelLength || utf8.RuneCountInString(s.Subtitle) > MaxSettingsLabelLength {
+		return fmt.Errorf("title and subtitle must be at most %d characters", MaxSettingsLabelLength)
+	}
+	if utf8.RuneCountInString(s.Text) > MaxSettingsTextLength {
+		return fmt.Errorf("text must be at most %d characters", MaxSettingsTextLength)
+	}
+	if s.ImageUrl != "" && !IsMediaURL(s.ImageUrl) {
+		return fmt.Errorf("image URL must be an http(s) URL or a site path")
+	}
+	if (s.ButtonLabel == "") != (s.ButtonUrl == "") {
+		return fmt.Errorf("button needs both a label and a URL")
+	}
+	if s.ButtonUrl != "" && !IsMediaURL(s.ButtonUrl) {
+		return fmt.Errorf("button URL must be an http(s) URL or a site path")
+	}
+	if utf8.RuneCountInString(s.ButtonLabel) > MaxSettingsLabelLength {
+		return fmt.Errorf("button label must be at most %d characters", MaxSettingsLabelLength)
+	}
+	return nil
+}
+
+// isWebURL reports whether s is an absolute http(s) URL.
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isSocialPlatform(platform string) bool {
+	for _, p := range SocialPlatforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func isWeekday(day string) bool {
+	for _, d := range Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultSiteSettingsAreValid(t *testing.T) {
+	settings := DefaultSiteSettings()
+	settings.Normalize()
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("Validate() = %v; want nil", err)
+	}
+}
+
+func TestSiteSettingsNormalize(t *testing.T) {
+	settings := SiteSettings{
+		Contacts:      []ContactChannel{{Type: " Email ", Value: " Studio@Example.com "}},
+		BusinessHours: []BusinessHours{{Day: "SAT", Open: "10:00", Close: "16:00", Closed: true}},
+		Seo:           SeoDefaults{Title: "  DsignMe   Studio "},
+	}
+	settings.Normalize()
+
+	if contact := settings.Contacts[0]; contact.Type != ContactChannelEmail || contact.Value != "studio@example.com" {
+		t.Errorf("contact = %+v", contact)
+	}
+	if hours := settings.BusinessHours[0]; hours.Day != "sat" || hours.Open != "" || hours.Close != "" {
+		t.Errorf("closed day keeps its hours: %+v", hours)
+	}
+	if settings.Social == nil || settings.HeroSlides == nil {
+		t.Errorf("missing lists stay nil: %+v", settings)
+	}
+	if settings.Seo.Title != "DsignMe Studio" {
+		t.Errorf("SEO title = %q", settings.Seo.Title)
+	}
+}
+
+func TestSiteSettingsValidate(t *testing.T) {
+	valid := func() SiteSettings {
+		settings := DefaultSiteSettings()
+		settings.Contacts = append(settings.Contacts, ContactChannel{Type: ContactChannelPhone, Value: "081 234 5678"})
+		settings.BusinessHours = []BusinessHours{
+			{Day: "mon", Open: "09:00", Close: "18:00"},
+			{Day: "sun", Closed: true},
+		}
+		return settings
+	}
+	settings := valid()
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("Validate() = %v; want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*SiteSettings)
+		want   string
+	}{
+		{"unknown contact type", func(s *SiteSettings) { s.Contacts[1].Type = "fax" }, "Contact channel 2"},
+		{"bad email", func(s *SiteSettings) { s.Contacts[1].Value = "studio@" }, "invalid email"},
+		{"bad phone", func(s *SiteSettings) { s.Contacts[2].Value = "call us" }, "invalid phone"},
+		{"LINE without link", func(s *SiteSettings) { s.Contacts[0].Url = "" }, "add-friend URL"},
+		{"unknown platform", func(s *SiteSettings) { s.Social[0].Platform = "myspace" }, "Social link 1"},
+		{"relative social link", func(s *SiteSettings) { s.Social[1].Url = "/instagram" }, "http(s) URL"},
+		{"unknown day", func(s *SiteSettings) { s.BusinessHours[0].Day = "monday" }, "Business hours 1"},
+		{"day twice", func(s *SiteSettings) { s.BusinessHours[1].Day = "mon" }, "listed twice"},
+		{"bad time", func(s *SiteSettings) { s.BusinessHours[0].Open = "9am" }, "HH:MM"},
+		{"closes before opening", func(s *SiteSettings) { s.BusinessHours[0].Close = "08:00" }, "after opening"},
+		{"slide without title", func(s *SiteSettings) { s.HeroSlides[0].Title = "" }, "Hero slide 1"},
+		{"button without URL", func(s *SiteSettings) { s.HeroSlides[0].ButtonUrl = "" }, "label and a URL"},
+		{"too many slides", func(s *SiteSettings) { s.HeroSlides = make([]HeroSlide, MaxHeroSlides+1) }, "hero slides"},
+		{"missing SEO title", func(s *SiteSettings) { s.Seo.Title = "" }, "SEO title"},
+		{"long SEO description", func(s *SiteSettings) { s.Seo.Description = strings.Repeat("ก", MaxSeoDescriptionLength+1) }, "SEO description"},
+	}
+	for _, test := range tests {
+		settings := valid()
+		test.change(&settings)
+		err := settings.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: Validate() = %v; want an error containing %q", test.name, err, test.want)
+		}
+	}
+}
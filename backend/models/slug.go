@@ -0,0 +1,42 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Slugify turns a display name into a URL slug. Letters, digits and combining
+// marks of any script are kept (so Thai names stay readable), everything else
+// collapses into single dashes.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if b.Len() > 0 && !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// SlugFromParam turns a URL path param into a slug. Path params arrive still
+// percent-encoded, so a Thai slug has to be decoded before it can match; a param
+// that is not valid encoding is slugified as it is.
+func SlugFromParam(param string) string {
+	if decoded, err := url.PathUnescape(param); err == nil {
+		param = decoded
+	}
+	return Slugify(param)
+}
+
+// IsSlugValid reports whether s is already in canonical slug form.
+func IsSlugValid(s string) bool {
+	return s != "" && Slugify(s) == s
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Logo", "logo"},
+		{"Website Develop", "website-develop"},
+		{"  Visual -- Design!  ", "visual-design"},
+		{"โลโก้ ร้านอาหาร", "โลโก้-ร้านอาหาร"},
+		{"E-commerce 2024", "e-commerce-2024"},
+		{"!!!", ""},
+	}
+
+	for _, test := range tests {
+		result := Slugify(test.name)
+		if result != test.expected {
+			t.Errorf("Slugify(%q) = %q; want %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestSlugFromParam(t *testing.T) {
+	tests := []struct {
+		param    string
+		expected string
+	}{
+		{"logo", "logo"},
+		{"Visual%20Design", "visual-design"},
+		{url.PathEscape("โลโก้-ร้านอาหาร"), "โลโก้-ร้านอาหาร"},
+		{"โลโก้-ร้านอาหาร", "โลโก้-ร้านอาหาร"},
+		{"100%-design", "100-design"},
+	}
+
+	for _, test := range tests {
+		result := SlugFromParam(test.param)
+		if result != test.expected {
+			t.Errorf("SlugFromParam(%q) = %q; want %q", test.param, result, test.expected)
+		}
+	}
+}
+
+func TestIsSlugValid(t *testing.T) {
+	tests := []struct {
+		slug     string
+		expected bool
+	}{
+		{"logo", true},
+		{"website-develop", true},
+		{"Logo", false},
+		{"logo-", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		result := IsSlugValid(test.slug)
+		if result != test.expected {
+			t.Errorf("IsSlugValid(%q) = %v; want %v", test.slug, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodCount is the number of items in one week ("2024-W07") or month ("2024-02").
+type PeriodCount struct {
+	Period string `json:"period" bson:"_id"`
+	Count  int    `json:"count" bson:"count"`
+}
+
+// WeekPeriod formats t as an ISO week, the same as Mongo's "%G-W%V" date format.
+func WeekPeriod(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// MonthPeriod formats t as a month, the same as Mongo's "%Y-%m" date format.
+func MonthPeriod(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// WeekPeriods lists the n weeks up to and including the one containing now, oldest first.
+func WeekPeriods(now time.Time, n int) []string {
+	periods := make([]string, n)
+	for i := 0; i < n; i++ {
+		periods[n-1-i] = WeekPeriod(now.AddDate(0, 0, -7*i))
+	}
+	return periods
+}
+
+// MonthPeriods lists the n months up to and including the one containing now, oldest first.
+func MonthPeriods(now time.Time, n int) []string {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	periods := make([]string, n)
+	for i := 0; i < n; i++ {
+		periods[n-1-i] = MonthPeriod(first.AddDate(0, -i, 0))
+	}
+	return periods
+}
+
+// DayPeriods lists the n days (YYYY-MM-DD) up to and including now, oldest first.
+func DayPeriods(now time.Time, n int) []string {
+	periods := make([]string, n)
+	for i := 0; i < n; i++ {
+		periods[n-1-i] = now.AddDate(0, 0, -i).Format("2006-01-02")
+	}
+	return periods
+}
+
+// FillPeriodCounts returns a count for every period, using zero where counts has none, so
+// charts get a continuous series.
+func FillPeriodCounts(periods []string, counts []PeriodCount) []PeriodCount {
+	byPeriod := map[string]int{}
+	for _, count := range counts {
+		byPeriod[count.Period] = count.Count
+	}
+	filled := make([]PeriodCount, len(periods))
+	for i, period := range periods {
+		filled[i] = PeriodCount{Period: period, Count: byPeriod[period]}
+	}
+	return filled
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWeekPeriods(t *testing.T) {
+	// 2021-01-03 is a Sunday that still belongs to ISO week 53 of 2020
+	now := time.Date(2021, 1, 10, 12, 0, 0, 0, time.UTC)
+	got := WeekPeriods(now, 3)
+	want := []string{"2020-W52", "2020-W53", "2021-W01"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WeekPeriods() = %v; want %v", got, want)
+	}
+}
+
+func TestMonthPeriods(t *testing.T) {
+	// The 31st must not skip February when stepping back a month
+	now := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	got := MonthPeriods(now, 3)
+	want := []string{"2024-01", "2024-02", "2024-03"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MonthPeriods() = %v; want %v", got, want)
+	}
+}
+
+func TestDayPeriods(t *testing.T) {
+	got := DayPeriods(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), 3)
+	want := []string{"2024-02-28", "2024-02-29", "2024-03-01"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DayPeriods() = %v; want %v", got, want)
+	}
+}
+
+func TestFillPeriodCounts(t *testing.T) {
+	got := FillPeriodCounts([]string{"2024-01", "2024-02", "2024-03"}, []PeriodCount{{Period: "2024-02", Count: 4}, {Period: "2023-12", Count: 9}})
+	want := []PeriodCount{{"2024-01", 0}, {"2024-02", 4}, {"2024-03", 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FillPeriodCounts() = %v; want %v", got, want)
+	}
+}
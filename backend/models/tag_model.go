@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tag groups projects across categories, e.g. by industry, style or client. Projects
+// reference tags by Slug in their Tags field.
+type Tag struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name      string             `bson:"name" json:"name"`
+	Slug      string             `bson:"slug" json:"slug"`
+	Group     string             `bson:"group,omitempty" json:"group,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version   int64              `bson:"version" json:"version"`
+}
+
+// TagFacet is the number of projects carrying a tag within the current listing filter.
+type TagFacet struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Group string `json:"group,omitempty"`
+	Count int    `json:"count"`
+}
+
+// ParseTagList reads a comma separated list of tags as slugs, dropping blanks and duplicates.
+// Display names are accepted too and slugified the same way as when the tag was created.
+func ParseTagList(raw string) []string {
+	var slugs []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		slug := Slugify(part)
+		if slug != "" && !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTagList(t *testing.T) {
+	got := ParseTagList(" restaurant, Minimal Style,,restaurant , ร้านกาแฟ")
+	want := []string{"restaurant", "minimal-style", "ร้านกาแฟ"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := ParseTagList(""); got != nil {
+		t.Fatalf("got %v for empty input, want nil", got)
+	}
+}
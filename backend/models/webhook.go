@@ -0,0 +1,109 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Delivery statuses of a webhook call.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+const (
+	// MaxWebhookAttempts is how often a delivery is tried before it is given up.
+	MaxWebhookAttempts = 6
+	// webhookBaseBackoff is the wait after the first failed attempt; it doubles after
+	// every further failure.
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = time.Hour
+)
+
+// Webhook is an endpoint that receives the events it subscribed to. The secret is only
+// shown when the webhook is created or the secret is rotated.
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	URL         string             `bson:"url" json:"url"`
+	Events      []string           `bson:"events" json:"events"`
+	Secret      string             `bson:"secret" json:"-"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Active      bool               `bson:"active" json:"active"`
+	CreatedBy   string             `bson:"createdBy" json:"createdBy"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version     int64              `bson:"version" json:"version"`
+}
+
+// WebhookDelivery is one event sent to one webhook, with every attempt made so far.
+// Payload keeps the exact body so retries send the same bytes.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	WebhookID     primitive.ObjectID `bson:"webhookId" json:"webhookId"`
+	EventID       string             `bson:"eventId" json:"eventId"`
+	Event         string             `bson:"event" json:"event"`
+	Payload       string             `bson:"payload" json:"payload"`
+	Status        string             `bson:"status" json:"status"`
+	Attempts      []WebhookAttempt   `bson:"attempts" json:"attempts"`
+	NextAttemptAt *time.Time         `bson:"nextAttemptAt,omitempty" json:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	DeliveredAt   *time.Time         `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+}
+
+// WebhookAttempt is the outcome of a single call.
+type WebhookAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"statusCode,omitempty" json:"statusCode,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int64     `bson:"durationMs" json:"durationMs"`
+}
+
+// Validate checks the endpoint URL and the event filters.
+func (w *Webhook) Validate() error {
+	endpoint, err := url.Parse(w.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("URL must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("At least one event is required")
+	}
+	for _, event := range w.Events {
+		if !IsEventFilter(event) {
+			return fmt.Errorf("Unknown event %s", event)
+		}
+	}
+	return nil
+}
+
+// SignWebhook returns the signature sent in X-Webhook-Signature: the hex HMAC-SHA256 of
+// "<timestamp>.<body>". Receivers recompute it and reject old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookBackoff is how long to wait after the given number of failed attempts.
+func WebhookBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := webhookBaseBackoff
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxBackoff {
+		wait = webhookMaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventMatches(t *testing.T) {
+	cases := []struct {
+		filters []string
+		event   string
+		want    bool
+	}{
+		{[]string{"project.created"}, "project.created", true},
+		{[]string{"project.created"}, "project.updated", false},
+		{[]string{"project.*"}, "project.deleted", true},
+		{[]string{"project.*"}, "category.deleted", false},
+		{[]string{"contact.created", "*"}, "servicestep.updated", true},
+		{nil, "project.created", false},
+	}
+	for _, tc := range cases {
+		if got := EventMatches(tc.filters, tc.event); got != tc.want {
+			t.Errorf("EventMatches(%v, %q) = %t; want %t", tc.filters, tc.event, got, tc.want)
+		}
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	valid := Webhook{URL: "http://localhost:9000/hook", Events: []string{"project.*", "contact.created"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v; want nil", err)
+	}
+
+	for _, w := range []Webhook{
+		{URL: "ftp://example.com", Events: []string{"*"}},
+		{URL: "/relative", Events: []string{"*"}},
+		{URL: "https://example.com", Events: nil},
+		{URL: "https://example.com", Events: []string{"project.renamed"}},
+		{URL: "https://example.com", Events: []string{"unknown.*"}},
+	} {
+		if err := w.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil; want error", w)
+		}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1700000000.{"type":"ping"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=5a2a8f7d964e86f8fb6f3a65e439891e4154c53e76bca44002f5200ba270fdf6"
+	got := SignWebhook("secret", 1700000000, []byte(`{"type":"ping"}`))
+	if got != want {
+		t.Fatalf("SignWebhook = %q; want %q", got, want)
+	}
+	if other := SignWebhook("other", 1700000000, []byte(`{"type":"ping"}`)); other == got {
+		t.Errorf("signatures with different secrets match")
+	}
+	if other := SignWebhook("secret", 1700000001, []byte(`{"type":"ping"}`)); other == got {
+		t.Errorf("signatures with different timestamps match")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  30 * time.Second,
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		5:  8 * time.Minute,
+		20: time.Hour,
+	}
+	for attempts, want := range cases {
+		if got := WebhookBackoff(attempts); got != want {
+			t.Errorf("WebhookBackoff(%d) = %v; want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	linePushEndpoint = "https://api.line.me/v2/bot/message/push"
+	// maxLineText is the LINE limit for a text message
+	maxLineText = 5000
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// logChannel writes notifications to stdout, where Render keeps them with the other logs.
+type logChannel struct{}
+
+func (logChannel) Send(ctx context.Context, msg Message) error {
+	log.Printf("notification [%s] %s\n%s", msg.Event, msg.Subject, msg.Text)
+	return nil
+}
+
+// fileChannel appends notifications to a file as JSON lines, so they can be inspected
+// during development and tests without any account.
+type fileChannel struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *fileChannel) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"at":      time.Now(),
+		"event":   msg.Event,
+		"subject": msg.Subject,
+		"text":    msg.Text,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// lineChannel pushes a text message to LINE users or groups with the Messaging API.
+type lineChannel struct {
+	endpoint string
+	token    string
+	to       []string
+}
+
+func (l *lineChannel) Send(ctx context.Context, msg Message) error {
+	text := msg.Subject + "\n\n" + msg.Text
+	if utf8.RuneCountInString(text) > maxLineText {
+		text = string([]rune(text)[:maxLineText-1]) + "…"
+	}
+	for _, to := range l.to {
+		body, err := json.Marshal(map[string]interface{}{
+			"to":       to,
+			"messages": []map[string]string{{"type": "text", "text": text}},
+		})
+		if err != nil {
+			return err
+		}
+		if err := postJSON(ctx, l.endpoint, body, map[string]string{"Authorization": "Bearer " + l.token}); err != nil {
+			return fmt.Errorf("push to %s: %w", to, err)
+		}
+	}
+	return nil
+}
+
+// webhookChannel posts the message as JSON. The text field makes it work with Slack
+// incoming webhooks as is.
+type webhookChannel struct {
+	url string
+}
+
+func (w *webhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]string{
+		"event":   msg.Event,
+		"subject": msg.Subject,
+		"text":    msg.Subject + "\n" + msg.Text,
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.url, body, nil)
+}
+
+// smtpChannel sends plain text email. smtp.SendMail upgrades to TLS with STARTTLS when
+// the server offers it, so use the submission port 587 rather than 465.
+type smtpChannel struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func (s *smtpChannel) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, s.to, buildEmail(s.from, s.to, msg, time.Now()))
+}
+
+// buildEmail formats a UTF-8 plain text message; the Thai subject and body are encoded so
+// they survive every mail server on the way.
+func buildEmail(from string, to []string, msg Message, date time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Text))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+func postJSON(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
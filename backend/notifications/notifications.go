@@ -0,0 +1,180 @@
+// Package notifications tells the studio about events such as new leads and uploads over
+// LINE, email or a chat webhook. Channels and the events routed to them are configured
+// through environment variables, see Setup.
+package notifications
+
+import (
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+const sendTimeout = 15 * time.Second
+
+// defaultRoutes is used when NOTIFY_ROUTES is not set: leads and uploads are only logged.
+const defaultRoutes = "contact.created=log;quote.created=log;file.uploaded=log"
+
+// Message is a notification rendered for one locale.
+type Message struct {
+	Event   string
+	Subject string
+	Text    string
+}
+
+// Channel delivers messages to one destination.
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Route sends events matching Filter (an event type, "project.*" or "*") to Channel,
+// rendered in Locale.
+type Route struct {
+	Filter  string
+	Channel string
+	Locale  string
+}
+
+var (
+	channels = map[string]Channel{"log": logChannel{}}
+	routes   []Route
+)
+
+// Setup configures the channels from the environment and parses the routes:
+//
+//	NOTIFY_ROUTES      event=channel[:locale],...;event=... (locale th or en, default NOTIFY_LOCALE)
+//	NOTIFY_LOCALE      default locale, th when unset
+//	NOTIFY_LINE_TOKEN  LINE Messaging API channel access token
+//	NOTIFY_LINE_TO     comma separated user or group IDs to push to
+//	NOTIFY_SMTP_HOST, NOTIFY_SMTP_PORT (587), NOTIFY_SMTP_USER, NOTIFY_SMTP_PASSWORD,
+//	NOTIFY_SMTP_FROM, NOTIFY_SMTP_TO (comma separated)
+//	NOTIFY_WEBHOOK_URL generic JSON webhook, e.g. a Slack incoming webhook
+//	NOTIFY_FILE        file to append notifications to as JSON lines, for development
+//
+// The log channel is always available. Routes to channels that are not configured are
+// skipped with a warning.
+func Setup() {
+	channels = map[string]Channel{"log": logChannel{}}
+	if path := os.Getenv("NOTIFY_FILE"); path != "" {
+		channels["file"] = &fileChannel{path: path}
+	}
+	if token, to := os.Getenv("NOTIFY_LINE_TOKEN"), splitList(os.Getenv("NOTIFY_LINE_TO")); token != "" && len(to) > 0 {
+		channels["line"] = &lineChannel{endpoint: linePushEndpoint, token: token, to: to}
+	}
+	if host, to := os.Getenv("NOTIFY_SMTP_HOST"), splitList(os.Getenv("NOTIFY_SMTP_TO")); host != "" && len(to) > 0 {
+		port := os.Getenv("NOTIFY_SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		channels["email"] = &smtpChannel{
+			addr:     host + ":" + port,
+			host:     host,
+			username: os.Getenv("NOTIFY_SMTP_USER"),
+			password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			from:     os.Getenv("NOTIFY_SMTP_FROM"),
+			to:       to,
+		}
+	}
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		channels["webhook"] = &webhookChannel{url: url}
+	}
+
+	locale := os.Getenv("NOTIFY_LOCALE")
+	if locale == "" {
+		locale = models.DefaultLocale
+	}
+	raw := os.Getenv("NOTIFY_ROUTES")
+	if raw == "" {
+		raw = defaultRoutes
+	}
+	parsed, err := ParseRoutes(raw, locale)
+	if err != nil {
+		log.Fatalf("❌ NOTIFY_ROUTES is invalid: %v", err)
+	}
+
+	routes = nil
+	for _, route := range parsed {
+		if _, ok := channels[route.Channel]; !ok {
+			log.Printf("⚠️ Notification channel %s is not configured, skipping %s", route.Channel, route.Filter)
+			continue
+		}
+		routes = append(routes, route)
+	}
+}
+
+// ParseRoutes reads routes written as event=channel[:locale],...;event=... Channels without
+// a locale use defaultLocale.
+func ParseRoutes(raw, defaultLocale string) ([]Route, error) {
+	if !models.IsLocaleSupported(defaultLocale) {
+		return nil, fmt.Errorf("unknown locale %s", defaultLocale)
+	}
+	var parsed []Route
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		filter, targets, ok := strings.Cut(entry, "=")
+		filter = strings.TrimSpace(filter)
+		if !ok || !models.IsEventFilter(filter) {
+			return nil, fmt.Errorf("unknown event in %q", entry)
+		}
+		for _, target := range splitList(targets) {
+			channel, locale, hasLocale := strings.Cut(target, ":")
+			if !hasLocale {
+				locale = defaultLocale
+			}
+			if channel == "" || !models.IsLocaleSupported(locale) {
+				return nil, fmt.Errorf("invalid channel %q for %s", target, filter)
+			}
+			parsed = append(parsed, Route{Filter: filter, Channel: channel, Locale: locale})
+		}
+	}
+	return parsed, nil
+}
+
+// Notify sends the event to every channel routed to it, in the background.
+func Notify(event models.Event) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		notify(ctx, event)
+	}()
+}
+
+func notify(ctx context.Context, event models.Event) {
+	// One message per channel and locale, even when several routes match
+	sent := map[Route]bool{}
+	for _, route := range routes {
+		if !models.EventMatches([]string{route.Filter}, event.Type) {
+			continue
+		}
+		key := Route{Channel: route.Channel, Locale: route.Locale}
+		if sent[key] {
+			continue
+		}
+		sent[key] = true
+
+		msg, err := Render(event, route.Locale)
+		if err != nil {
+			log.Printf("notify: Failed to render %s in %s: %v", event.Type, route.Locale, err)
+			continue
+		}
+		if err := channels[route.Channel].Send(ctx, msg); err != nil {
+			log.Printf("notify: Failed to send %s over %s: %v", event.Type, route.Channel, err)
+		}
+	}
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,195 @@
+package notifications
+
+import (
+	"backend/models"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var occurredAt = time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)
+
+func contactEvent() models.Event {
+	return models.Event{
+		Type: models.EventContactCreated,
+		Data: models.Contact{
+			Name:    "สมชาย ใจดี",
+			Email:   "somchai@example.com",
+			Phone:   "081 234 5678",
+			Message: "อยากทำโลโก้ร้านกาแฟ",
+		},
+		OccurredAt: occurredAt,
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	got, err := ParseRoutes("contact.created=line,email:en; project.*=webhook", "th")
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+	want := []Route{
+		{Filter: "contact.created", Channel: "line", Locale: "th"},
+		{Filter: "contact.created", Channel: "email", Locale: "en"},
+		{Filter: "project.*", Channel: "webhook", Locale: "th"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRoutes = %+v; want %+v", got, want)
+	}
+
+	for _, raw := range []string{
+		"contact.created",
+		"contact.renamed=line",
+		"contact.created=line:de",
+		"contact.created=:en",
+	} {
+		if _, err := ParseRoutes(raw, "th"); err == nil {
+			t.Errorf("ParseRoutes(%q) = nil error", raw)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	msg, err := Render(contactEvent(), "th")
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.Subject != "ข้อความติดต่อใหม่จาก สมชาย ใจดี" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	for _, part := range []string{"อีเมล: somchai@example.com", "โทร: 081 234 5678", "อยากทำโลโก้ร้านกาแฟ", "01/05/2024 10:30"} {
+		if !strings.Contains(msg.Text, part) {
+			t.Errorf("text %q does not contain %q", msg.Text, part)
+		}
+	}
+	if strings.Contains(msg.Text, "งบประมาณ") {
+		t.Errorf("text %q shows an empty budget", msg.Text)
+	}
+
+	msg, err = Render(models.Event{
+		Type:       models.EventFileUploaded,
+		Actor:      "admin@example.com",
+		Data:       bson.M{"filename": "logo.png", "type": "image", "size": int64(2 * 1024 * 1024), "url": "https://api.example.com/files/1"},
+		OccurredAt: occurredAt,
+	}, "en")
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.Subject != "New upload: logo.png" || !strings.Contains(msg.Text, "admin@example.com uploaded logo.png (image, 2.0 MB)") {
+		t.Errorf("Render(file.uploaded) = %+v", msg)
+	}
+
+	// Events without a template of their own use the generic one
+	msg, err = Render(models.Event{Type: models.EventTagDeleted, Actor: "admin@example.com", OccurredAt: occurredAt}, "en")
+	if err != nil || msg.Subject != "Event tag.deleted" {
+		t.Errorf("Render(tag.deleted) = %+v, %v", msg, err)
+	}
+}
+
+func TestFileChannelThroughRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	t.Setenv("NOTIFY_FILE", path)
+	t.Setenv("NOTIFY_ROUTES", "contact.created=file:en,line;quote.created=file")
+	t.Setenv("NOTIFY_LINE_TOKEN", "")
+	Setup()
+
+	// The LINE route is skipped as LINE is not configured
+	if len(routes) != 2 {
+		t.Fatalf("routes = %+v; want the two file routes", routes)
+	}
+
+	notify(context.Background(), contactEvent())
+	notify(context.Background(), models.Event{Type: models.EventProjectCreated, OccurredAt: occurredAt})
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("file has %d lines; want 1", len(lines))
+	}
+	var entry map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if entry["event"] != models.EventContactCreated || entry["subject"] != "New contact from สมชาย ใจดี" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestLineChannel(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+	}))
+	defer server.Close()
+
+	line := &lineChannel{endpoint: server.URL, token: "token", to: []string{"U1", "C2"}}
+	if err := line.Send(context.Background(), Message{Subject: "หัวข้อ", Text: "ข้อความ"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(requests) != 2 || requests[0]["to"] != "U1" || requests[1]["to"] != "C2" {
+		t.Fatalf("requests = %v", requests)
+	}
+	messages := requests[0]["messages"].([]interface{})
+	if text := messages[0].(map[string]interface{})["text"]; text != "หัวข้อ\n\nข้อความ" {
+		t.Errorf("text = %q", text)
+	}
+
+	line.token = "wrong"
+	if err := line.Send(context.Background(), Message{Subject: "s", Text: "t"}); err == nil {
+		t.Errorf("Send with a wrong token = nil error")
+	}
+}
+
+func TestWebhookChannel(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	hook := &webhookChannel{url: server.URL}
+	if err := hook.Send(context.Background(), Message{Event: "contact.created", Subject: "New", Text: "Body"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var got map[string]string
+	json.Unmarshal(body, &got)
+	if got["event"] != "contact.created" || got["text"] != "New\nBody" {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestBuildEmail(t *testing.T) {
+	raw := string(buildEmail("studio@example.com", []string{"a@example.com", "b@example.com"},
+		Message{Subject: "ข้อความใหม่", Text: "สวัสดี"}, occurredAt))
+
+	if !strings.Contains(raw, "To: a@example.com, b@example.com\r\n") {
+		t.Errorf("missing To header in %q", raw)
+	}
+	if !strings.Contains(raw, "Subject: =?UTF-8?b?") {
+		t.Errorf("subject is not encoded in %q", raw)
+	}
+	_, body, _ := strings.Cut(raw, "\r\n\r\n")
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(strings.TrimSpace(body), "\r\n", ""))
+	if err != nil || string(decoded) != "สวัสดี" {
+		t.Errorf("body decodes to %q, %v", decoded, err)
+	}
+}
@@ -0,0 +1,160 @@
+package notifications
+
+import (
+	"backend/models"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// messageTemplate renders the subject and text of one event in one locale. Data is the
+// event, so templates read the entity from .Data.
+type messageTemplate struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+var studioLocation = loadStudioLocation()
+
+func loadStudioLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.FixedZone("ICT", 7*60*60)
+	}
+	return location
+}
+
+var templateFuncs = template.FuncMap{
+	// datetime shows a time the way the studio reads it, in Bangkok time
+	"datetime": func(t time.Time) string {
+		return t.In(studioLocation).Format("02/01/2006 15:04")
+	},
+	"filesize": func(size interface{}) string {
+		var bytes float64
+		switch v := size.(type) {
+		case int64:
+			bytes = float64(v)
+		case int:
+			bytes = float64(v)
+		case float64:
+			bytes = v
+		}
+		if bytes >= 1024*1024 {
+			return fmt.Sprintf("%.1f MB", bytes/(1024*1024))
+		}
+		return fmt.Sprintf("%.0f KB", bytes/1024)
+	},
+	"join": strings.Join,
+}
+
+func newTemplate(subject, text string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(templateFuncs).Parse(subject)),
+		text:    template.Must(template.New("text").Funcs(templateFuncs).Parse(text)),
+	}
+}
+
+// templates per locale and event type. Events without one use the fallback of the locale.
+var templates = map[string]map[string]messageTemplate{
+	"th": {
+		models.EventContactCreated: newTemplate(
+			"ข้อความติดต่อใหม่จาก {{.Data.Name}}",
+			"มีลูกค้าติดต่อเข้ามาใหม่\n"+
+				"ชื่อ: {{.Data.Name}}\n"+
+				"อีเมล: {{.Data.Email}}\n"+
+				"{{with .Data.Phone}}โทร: {{.}}\n{{end}}"+
+				"{{with .Data.Budget}}งบประมาณ: {{.}}\n{{end}}"+
+				"\n{{.Data.Message}}\n\n"+
+				"เวลา: {{datetime .OccurredAt}}",
+		),
+		models.EventQuoteCreated: newTemplate(
+			"คำขอใบเสนอราคาใหม่จาก {{.Data.Name}}",
+			"มีคำขอใบเสนอราคาใหม่\n"+
+				"ชื่อ: {{.Data.Name}}\n"+
+				"อีเมล: {{.Data.Email}}\n"+
+				"{{with .Data.Phone}}โทร: {{.}}\n{{end}}"+
+				"{{range .Data.Items}}- {{.Title}}{{with .Options}} ({{join . \", \"}}){{end}}\n{{end}}"+
+				"{{with .Data.Attachments}}ไฟล์แนบ {{len .}} ไฟล์\n{{end}}"+
+				"{{with .Data.Message}}\n{{.}}\n{{end}}"+
+				"\nเวลา: {{datetime .OccurredAt}}",
+		),
+		models.EventFileUploaded: newTemplate(
+			"อัปโหลดไฟล์ใหม่: {{.Data.filename}}",
+			"{{.Actor}} อัปโหลดไฟล์ {{.Data.filename}} ({{.Data.type}}, {{filesize .Data.size}})\n"+
+				"{{.Data.url}}\n\n"+
+				"เวลา: {{datetime .OccurredAt}}",
+		),
+		models.EventProjectCreated: newTemplate(
+			"ผลงานใหม่: {{.Data.Title}}",
+			"{{.Actor}} เพิ่มผลงาน {{.Data.Title}}{{if not .Data.Published}} (ยังไม่เผยแพร่){{end}}\n\n"+
+				"เวลา: {{datetime .OccurredAt}}",
+		),
+		"": newTemplate(
+			"เหตุการณ์ {{.Type}}",
+			"{{.Type}}{{with .EntityID}} {{.Hex}}{{end}}{{with .Actor}} โดย {{.}}{{end}}\n"+
+				"เวลา: {{datetime .OccurredAt}}",
+		),
+	},
+	"en": {
+		models.EventContactCreated: newTemplate(
+			"New contact from {{.Data.Name}}",
+			"A new contact came in\n"+
+				"Name: {{.Data.Name}}\n"+
+				"Email: {{.Data.Email}}\n"+
+				"{{with .Data.Phone}}Phone: {{.}}\n{{end}}"+
+				"{{with .Data.Budget}}Budget: {{.}}\n{{end}}"+
+				"\n{{.Data.Message}}\n\n"+
+				"Time: {{datetime .OccurredAt}}",
+		),
+		models.EventQuoteCreated: newTemplate(
+			"New quote request from {{.Data.Name}}",
+			"A new quote request came in\n"+
+				"Name: {{.Data.Name}}\n"+
+				"Email: {{.Data.Email}}\n"+
+				"{{with .Data.Phone}}Phone: {{.}}\n{{end}}"+
+				"{{range .Data.Items}}- {{.Title}}{{with .Options}} ({{join . \", \"}}){{end}}\n{{end}}"+
+				"{{with .Data.Attachments}}{{len .}} attachment(s)\n{{end}}"+
+				"{{with .Data.Message}}\n{{.}}\n{{end}}"+
+				"\nTime: {{datetime .OccurredAt}}",
+		),
+		models.EventFileUploaded: newTemplate(
+			"New upload: {{.Data.filename}}",
+			"{{.Actor}} uploaded {{.Data.filename}} ({{.Data.type}}, {{filesize .Data.size}})\n"+
+				"{{.Data.url}}\n\n"+
+				"Time: {{datetime .OccurredAt}}",
+		),
+		models.EventProjectCreated: newTemplate(
+			"New project: {{.Data.Title}}",
+			"{{.Actor}} added the project {{.Data.Title}}{{if not .Data.Published}} (not published yet){{end}}\n\n"+
+				"Time: {{datetime .OccurredAt}}",
+		),
+		"": newTemplate(
+			"Event {{.Type}}",
+			"{{.Type}}{{with .EntityID}} {{.Hex}}{{end}}{{with .Actor}} by {{.}}{{end}}\n"+
+				"Time: {{datetime .OccurredAt}}",
+		),
+	},
+}
+
+// Render formats the event for locale, falling back to the default locale and to the
+// generic template of the locale when the event has no template of its own.
+func Render(event models.Event, locale string) (Message, error) {
+	byEvent, ok := templates[locale]
+	if !ok {
+		byEvent = templates[models.DefaultLocale]
+	}
+	tmpl, ok := byEvent[event.Type]
+	if !ok {
+		tmpl = byEvent[""]
+	}
+
+	var subject, text strings.Builder
+	if err := tmpl.subject.Execute(&subject, event); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.text.Execute(&text, event); err != nil {
+		return Message{}, err
+	}
+	return Message{Event: event.Type, Subject: subject.String(), Text: text.String()}, nil
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AdminRoutes(app *fiber.App) {
+	// Authenticated dashboard endpoints
+	adminRoute := app.Group("/admin", middleware.AuthMiddleware())
+	adminRoute.Get("/stats", controllers.GetDashboardStatsHandler)
+	adminRoute.Get("/audit", controllers.GetAuditLogHandler)
+
+	// Handle 404 for /admin routes
+	adminRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AnalyticsRoutes(app *fiber.App) {
+	// Public beacon; the limit only stops floods, a normal visit sends a handful of events
+	app.Post("/analytics/events", middleware.RateLimitMiddleware(120, time.Minute), controllers.TrackAnalyticsHandler)
+
+	// Authenticated reports
+	analyticsRoute := app.Group("/analytics", middleware.AuthMiddleware())
+	analyticsRoute.Get("/top", controllers.GetTopProjectsHandler)
+	analyticsRoute.Get("/trends", controllers.GetAnalyticsTrendsHandler)
+
+	// Handle 404 for /analytics routes
+	analyticsRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
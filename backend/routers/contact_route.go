@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ContactRoutes(app *fiber.App) {
+	// Public contact form, limited per IP to keep spam out of the lead queue
+	app.Post("/contacts", middleware.RateLimitMiddleware(5, 15*time.Minute), controllers.AddContactHandler)
+
+	// Authenticated lead management
+	contactRoute := app.Group("/contacts", middleware.AuthMiddleware())
+	contactRoute.Get("/", controllers.GetContactsHandler)
+	contactRoute.Get("/:id", controllers.GetContactHandler)
+	contactRoute.Put("/:id/status", controllers.UpdateContactStatusHandler)
+	contactRoute.Put("/:id/assignee", controllers.AssignContactHandler)
+	contactRoute.Post("/:id/notes", controllers.AddContactNoteHandler)
+
+	// Handle 404 for /contacts routes
+	contactRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func EventRoutes(app *fiber.App) {
+	// Live updates for open dashboards; EventSource sends the token as a query parameter
+	app.Get("/events", middleware.StreamAuthMiddleware(), controllers.GetEventsHandler)
+}
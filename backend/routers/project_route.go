@@ -8,11 +8,14 @@ import (
 
 func ProjectRoutes(app *fiber.App) {
 	// Public routes (no authentication required)
-	app.Get("/projects", controllers.GetAllProjectsHandler)
+	app.Get("/projects", middleware.OptionalAuthMiddleware(), controllers.GetAllProjectsHandler)
 	// Move /projects/categories before /projects/:category
-	app.Get("/projects/categories", controllers.GetCategoriesHandler)
-	app.Get("/projects/:category", controllers.GetProjectsByCategoryHandler)
-	app.Get("/servicesteps/:category/service-steps", controllers.GetAllServiceStepsHandler)
+	// Optional auth lets signed-in admins see hidden categories and unpublished projects on the public listings
+	app.Get("/projects/categories", middleware.OptionalAuthMiddleware(), controllers.GetCategoriesHandler)
+	app.Get("/projects/categories/tree", middleware.OptionalAuthMiddleware(), controllers.GetCategoryTreeHandler)
+	app.Get("/projects/:category", middleware.OptionalAuthMiddleware(), controllers.GetProjectsByCategoryHandler)
+	app.Get("/projects/:category/:idOrSlug", middleware.OptionalAuthMiddleware(), controllers.GetProjectHandler)
+	app.Get("/servicesteps/:category/service-steps", middleware.OptionalAuthMiddleware(), controllers.GetAllServiceStepsHandler)
 
 	// Authenticated routes
 	route := app.Group("/projects", middleware.AuthMiddleware())
@@ -20,24 +23,49 @@ func ProjectRoutes(app *fiber.App) {
 	// Route for file upload
 	route.Post("/files", controllers.UploadFileHandler)
 
+	// Register /bulk before the /:category group so it is not captured as a category
+	route.Post("/bulk", controllers.BulkProjectsHandler)
+
 	// Categories routes (authenticated CRUD operations)
 	route.Post("/categories", controllers.AddCategoryHandler)
 	route.Put("/categories/:id", controllers.UpdateCategoryHandler)
+	route.Put("/categories/:id/parent", controllers.MoveCategoryHandler)
 	route.Delete("/categories/:id", controllers.DeleteCategoryHandler)
+	route.Put("/categories/:id/translations/:locale", controllers.UpdateCategoryTranslationHandler)
+	route.Delete("/categories/:id/translations/:locale", controllers.DeleteCategoryTranslationHandler)
 
 	// Dynamic category routes for projects (authenticated CRUD operations)
 	categoryRoute := route.Group("/:category")
 	categoryRoute.Post("/", controllers.AddProjectHandler)
+	// Register /order before /:id so it is not captured as a project ID
+	categoryRoute.Put("/order", controllers.ReorderProjectsHandler)
+	categoryRoute.Post("/move", controllers.MoveProjectsHandler)
+	categoryRoute.Post("/copy", controllers.CopyProjectsHandler)
 	categoryRoute.Put("/:id", controllers.UpdateProjectHandler)
+	categoryRoute.Put("/:id/pin", controllers.SetProjectPinnedHandler)
+	categoryRoute.Put("/:id/tags", controllers.SetProjectTagsHandler)
+	categoryRoute.Post("/:id/move", controllers.MoveProjectHandler)
+	categoryRoute.Post("/:id/copy", controllers.CopyProjectHandler)
+	categoryRoute.Put("/:id/translations/:locale", controllers.UpdateProjectTranslationHandler)
+	categoryRoute.Delete("/:id/translations/:locale", controllers.DeleteProjectTranslationHandler)
 	categoryRoute.Delete("/:id", controllers.DeleteProjectHandler)
+	categoryRoute.Get("/:id/revisions", controllers.GetProjectRevisionsHandler)
+	categoryRoute.Get("/:id/revisions/diff", controllers.DiffProjectRevisionsHandler)
+	categoryRoute.Post("/:id/revisions/:revision/restore", controllers.RestoreProjectRevisionHandler)
 
 	// Service steps routes (authenticated)
 	serviceStepsRoute := app.Group("/servicesteps", middleware.AuthMiddleware())
 	serviceStepsCategoryRoute := serviceStepsRoute.Group("/:category")
 	serviceStepsCategoryRoute.Get("/service-steps/:stepId", controllers.GetServiceStepHandler)
 	serviceStepsCategoryRoute.Post("/service-steps", controllers.AddServiceStepHandler)
+	serviceStepsCategoryRoute.Put("/service-steps/order", controllers.ReorderServiceStepsHandler)
 	serviceStepsCategoryRoute.Put("/service-steps/:stepId", controllers.UpdateServiceStepsHandler)
 	serviceStepsCategoryRoute.Delete("/service-steps/:stepId", controllers.DeleteServiceStepHandler)
+	serviceStepsCategoryRoute.Put("/service-steps/:stepId/translations/:locale", controllers.UpdateServiceStepTranslationHandler)
+	serviceStepsCategoryRoute.Delete("/service-steps/:stepId/translations/:locale", controllers.DeleteServiceStepTranslationHandler)
+	serviceStepsCategoryRoute.Get("/service-steps/:stepId/revisions", controllers.GetServiceStepRevisionsHandler)
+	serviceStepsCategoryRoute.Get("/service-steps/:stepId/revisions/diff", controllers.DiffServiceStepRevisionsHandler)
+	serviceStepsCategoryRoute.Post("/service-steps/:stepId/revisions/:revision/restore", controllers.RestoreServiceStepRevisionHandler)
 
 	// Route for file download (no authentication required)
 	app.Get("/files/:id", controllers.GetFileHandler)
@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func QuoteRoutes(app *fiber.App) {
+	// Public quote form; uploads make it more expensive than the contact form, so the limit is lower
+	app.Post("/quotes", middleware.RateLimitMiddleware(3, 15*time.Minute), controllers.AddQuoteRequestHandler)
+
+	// Authenticated quote queue
+	quoteRoute := app.Group("/quotes", middleware.AuthMiddleware())
+	quoteRoute.Get("/", controllers.GetQuoteRequestsHandler)
+	quoteRoute.Get("/:id", controllers.GetQuoteRequestHandler)
+	quoteRoute.Put("/:id/status", controllers.UpdateQuoteStatusHandler)
+	quoteRoute.Post("/:id/quote", controllers.SendQuoteHandler)
+	quoteRoute.Get("/:id/files/:fileId", controllers.GetQuoteFileHandler)
+
+	// Handle 404 for /quotes routes
+	quoteRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SearchRoutes(app *fiber.App) {
+	// Public search; signed-in admins also find unpublished projects and hidden categories
+	app.Get("/search", middleware.OptionalAuthMiddleware(), controllers.SearchHandler)
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"backend/controllers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SeoRoutes(app *fiber.App) {
+	// Public routes for crawlers, feed readers and link previews; only published content
+	// of visible categories is listed
+	app.Get("/sitemap.xml", controllers.GetSitemapHandler)
+	app.Get("/feeds/:category", controllers.GetCategoryFeedHandler)
+	app.Get("/og/:category", controllers.GetCategoryCardHandler)
+	app.Get("/og/:category/:id", controllers.GetProjectCardHandler)
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SettingsRoutes(app *fiber.App) {
+	// Public read for the user site
+	app.Get("/settings", controllers.GetSiteSettingsHandler)
+
+	// Authenticated updates and change history
+	settingsRoute := app.Group("/settings", middleware.AuthMiddleware())
+	settingsRoute.Put("/", controllers.UpdateSiteSettingsHandler)
+	settingsRoute.Get("/revisions", controllers.GetSiteSettingsRevisionsHandler)
+	settingsRoute.Get("/revisions/diff", controllers.DiffSiteSettingsRevisionsHandler)
+	settingsRoute.Post("/revisions/:revision/restore", controllers.RestoreSiteSettingsRevisionHandler)
+
+	// Handle 404 for /settings routes
+	settingsRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TagRoutes(app *fiber.App) {
+	// Public route (no authentication required)
+	app.Get("/tags", controllers.GetTagsHandler)
+
+	// Authenticated tag management
+	tagRoute := app.Group("/tags", middleware.AuthMiddleware())
+	tagRoute.Post("/", controllers.AddTagHandler)
+	tagRoute.Put("/:id", controllers.UpdateTagHandler)
+	tagRoute.Delete("/:id", controllers.DeleteTagHandler)
+
+	// Handle 404 for /tags routes
+	tagRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}
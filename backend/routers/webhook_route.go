@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func WebhookRoutes(app *fiber.App) {
+	webhookRoute := app.Group("/webhooks", middleware.AuthMiddleware())
+	webhookRoute.Get("/", controllers.GetWebhooksHandler)
+	webhookRoute.Post("/", controllers.AddWebhookHandler)
+	// Literal paths before /:id
+	webhookRoute.Get("/events", controllers.GetWebhookEventsHandler)
+	webhookRoute.Post("/deliveries/:deliveryId/redeliver", controllers.RedeliverWebhookHandler)
+	webhookRoute.Put("/:id", controllers.UpdateWebhookHandler)
+	webhookRoute.Delete("/:id", controllers.DeleteWebhookHandler)
+	webhookRoute.Get("/:id/deliveries", controllers.GetWebhookDeliveriesHandler)
+	webhookRoute.Post("/:id/ping", controllers.PingWebhookHandler)
+
+	// Handle 404 for /webhooks routes
+	webhookRoute.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Route not found",
+		})
+	})
+}